@@ -5,6 +5,15 @@ import (
 	"time"
 
 	"github.com/pureapi/pureapi-core/database/types"
+	"github.com/pureapi/pureapi-core/util"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
+)
+
+// Constants for event types.
+const (
+	// EventConnectRetry event is emitted when pinging the database fails and
+	// the connection attempt will be retried.
+	EventConnectRetry utiltypes.EventType = "event_connect_retry"
 )
 
 // ConnectConfig holds the configuration for the database connection.
@@ -24,6 +33,17 @@ type ConnectConfig struct {
 	MaxOpenConns    int           // Max open connections
 	MaxIdleConns    int           // Max idle connections
 
+	// ConnectRetries is the number of times a failed ping is retried. Zero
+	// means a single connection attempt.
+	ConnectRetries int
+	// ConnectRetryInterval is the time to wait before the first retry.
+	ConnectRetryInterval time.Duration
+	// RetryBackoffFactor multiplies the retry interval after each failed
+	// retry. Values less than or equal to 1 keep the interval constant.
+	RetryBackoffFactor float64
+	// EmitterLogger is an optional emitter logger used to report retries.
+	EmitterLogger utiltypes.EmitterLogger
+
 	// DSNFormat is an optional format string (e.g. "%s:%s@tcp(%s:%d)/%s?%s").
 	// If present (non-empty), it will be used to generate the DSN (with
 	// fmt.Sprintf). You can embed placeholders for user, password, host,
@@ -37,7 +57,9 @@ type ConnOpenFn func(driver string, dsn string) (types.DB, error)
 
 // Connect establishes a connection to the database using the provided
 // configuration. It will automatically configure the connection based on the
-// provided configuration and then attempt to ping the database.
+// provided configuration and then attempt to ping the database. If the ping
+// fails, it is retried up to ConnectRetries times, waiting
+// ConnectRetryInterval (scaled by RetryBackoffFactor) between attempts.
 //
 // Parameters:
 //   - cfg: The configuration for the database connection.
@@ -51,25 +73,78 @@ func Connect(
 	cfg ConnectConfig,
 	connOpenFn ConnOpenFn,
 	dsn string,
+) (types.DB, error) {
+	return connect(cfg, connOpenFn, dsn, time.Sleep)
+}
+
+// connect opens the connection and pings it, using sleepFn between retries.
+func connect(
+	cfg ConnectConfig,
+	connOpenFn ConnOpenFn,
+	dsn string,
+	sleepFn func(d time.Duration),
 ) (types.DB, error) {
 	db, err := connOpenFn(cfg.Driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("Connect: failed to open database: %w", err)
 	}
-	return configureAndPingConnection(db, cfg)
+	return configureAndPingConnection(db, cfg, sleepFn)
 }
 
-// configureAndPingConnection configures the connection and pings the database.
+// configureAndPingConnection configures the connection and pings the database,
+// retrying the ping as configured.
 func configureAndPingConnection(
-	db types.DB, cfg ConnectConfig,
+	db types.DB, cfg ConnectConfig, sleepFn func(d time.Duration),
 ) (types.DB, error) {
 	configureConnection(db, cfg)
-	if err := db.Ping(); err != nil {
+	emitterLogger := cfg.EmitterLogger
+	if emitterLogger == nil {
+		emitterLogger = util.NewNoopEmitterLogger()
+	}
+	attempts := 1 + max(cfg.ConnectRetries, 0)
+	interval := cfg.ConnectRetryInterval
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = db.Ping(); err == nil {
+			return db, nil
+		}
+		if attempt == attempts {
+			break
+		}
+		emitterLogger.Warn(
+			utiltypes.NewEvent(
+				EventConnectRetry,
+				fmt.Sprintf(
+					"Failed to ping database (attempt %d/%d), retrying in %v: %v",
+					attempt, attempts, interval, err,
+				),
+			).WithData(map[string]any{
+				"attempt":  attempt,
+				"attempts": attempts,
+				"interval": interval,
+				"error":    err,
+			}),
+		)
+		sleepFn(interval)
+		interval = nextRetryInterval(interval, cfg.RetryBackoffFactor)
+	}
+	if attempts == 1 {
 		return nil, fmt.Errorf(
 			"configureAndPingConnection: failed to ping database: %w", err,
 		)
 	}
-	return db, nil
+	return nil, fmt.Errorf(
+		"configureAndPingConnection: failed to ping database after %d attempts: %w",
+		attempts, err,
+	)
+}
+
+// nextRetryInterval returns the interval to wait before the next retry.
+func nextRetryInterval(interval time.Duration, factor float64) time.Duration {
+	if factor <= 1 {
+		return interval
+	}
+	return time.Duration(float64(interval) * factor)
 }
 
 // configureConnection sets up the runtime connection limits.
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/pureapi/pureapi-core/database/types"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -18,6 +19,8 @@ type FakeDB struct {
 	DriverName      string
 	DSN             string
 	pingErr         error
+	pingFailures    int // If set, only the first pingFailures pings fail.
+	pingCount       int
 	connMaxLifetime time.Duration
 	connMaxIdleTime time.Duration
 	maxOpenConns    int
@@ -33,6 +36,10 @@ func NewFakeDB(driver, dsn string) *FakeDB {
 
 // Ping returns the configured ping error (nil if no error).
 func (f *FakeDB) Ping() error {
+	f.pingCount++
+	if f.pingFailures > 0 && f.pingCount > f.pingFailures {
+		return nil
+	}
 	return f.pingErr
 }
 
@@ -52,6 +59,10 @@ func (f *FakeDB) SetMaxIdleConns(n int) {
 	f.maxIdleConns = n
 }
 
+func (f *FakeDB) PingContext(ctx context.Context) error {
+	return f.Ping()
+}
+
 func (f *FakeDB) BeginTx(
 	ctx context.Context, options *sql.TxOptions,
 ) (types.Tx, error) {
@@ -66,12 +77,56 @@ func (f *FakeDB) Query(query string, args ...any) (types.Rows, error) {
 func (f *FakeDB) QueryRow(query string, args ...any) types.Row {
 	panic("not implemented")
 }
+func (f *FakeDB) ExecContext(
+	ctx context.Context, query string, args ...any,
+) (types.Result, error) {
+	panic("not implemented")
+}
+func (f *FakeDB) QueryContext(
+	ctx context.Context, query string, args ...any,
+) (types.Rows, error) {
+	panic("not implemented")
+}
+func (f *FakeDB) QueryRowContext(
+	ctx context.Context, query string, args ...any,
+) types.Row {
+	panic("not implemented")
+}
 func (f *FakeDB) Close() error {
 	return nil
 }
 func (f *FakeDB) Prepare(query string) (types.Stmt, error) {
 	panic("not implemented")
 }
+func (f *FakeDB) PrepareContext(
+	ctx context.Context, query string,
+) (types.Stmt, error) {
+	panic("not implemented")
+}
+
+// fakeEmitterLogger records the events passed to it.
+type fakeEmitterLogger struct {
+	events []*utiltypes.Event
+}
+
+func (f *fakeEmitterLogger) Debug(event *utiltypes.Event, _ ...any) {
+	f.events = append(f.events, event)
+}
+func (f *fakeEmitterLogger) Trace(event *utiltypes.Event, _ ...any) {
+	f.events = append(f.events, event)
+}
+func (f *fakeEmitterLogger) Info(event *utiltypes.Event, _ ...any) {
+	f.events = append(f.events, event)
+}
+func (f *fakeEmitterLogger) Warn(event *utiltypes.Event, _ ...any) {
+	f.events = append(f.events, event)
+}
+func (f *fakeEmitterLogger) Error(event *utiltypes.Event, _ ...any) {
+	f.events = append(f.events, event)
+}
+func (f *fakeEmitterLogger) Fatal(event *utiltypes.Event, _ ...any) {
+	f.events = append(f.events, event)
+}
 
 func fakeConnOpenFn(driver string, dsn string) (types.DB, error) {
 	return NewFakeDB(driver, dsn), nil
@@ -157,3 +212,78 @@ func (s *ConnectionTestSuite) Test_PingError() {
 	require.Error(s.T(), err)
 	assert.Nil(s.T(), db)
 }
+
+// Test_Retry_SucceedsAfterFailures verifies that Connect retries a failing ping
+// with backoff and emits an event per retry.
+func (s *ConnectionTestSuite) Test_Retry_SucceedsAfterFailures() {
+	emitterLogger := &fakeEmitterLogger{}
+	cfg := s.cfg
+	cfg.ConnectRetries = 3
+	cfg.ConnectRetryInterval = 10 * time.Millisecond
+	cfg.RetryBackoffFactor = 2
+	cfg.EmitterLogger = emitterLogger
+	fake := NewFakeDB("driver", "dsn")
+	fake.pingErr = errors.New("ping failed")
+	fake.pingFailures = 2
+	var sleeps []time.Duration
+
+	db, err := connect(
+		cfg,
+		func(driver string, dsn string) (types.DB, error) { return fake, nil },
+		"dsn",
+		func(d time.Duration) { sleeps = append(sleeps, d) },
+	)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), db)
+	assert.Equal(s.T(), 3, fake.pingCount)
+	assert.Equal(
+		s.T(),
+		[]time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		sleeps,
+	)
+	require.Len(s.T(), emitterLogger.events, 2)
+	assert.Equal(s.T(), EventConnectRetry, emitterLogger.events[0].Type)
+}
+
+// Test_Retry_Exhausted verifies that Connect wraps the last ping error and
+// reports the number of attempts once all retries fail.
+func (s *ConnectionTestSuite) Test_Retry_Exhausted() {
+	cfg := s.cfg
+	cfg.ConnectRetries = 2
+	pingErr := errors.New("ping failed")
+	fake := NewFakeDB("driver", "dsn")
+	fake.pingErr = pingErr
+	sleepCount := 0
+
+	db, err := connect(
+		cfg,
+		func(driver string, dsn string) (types.DB, error) { return fake, nil },
+		"dsn",
+		func(d time.Duration) { sleepCount++ },
+	)
+	require.Error(s.T(), err)
+	assert.Nil(s.T(), db)
+	assert.ErrorIs(s.T(), err, pingErr)
+	assert.Contains(s.T(), err.Error(), "after 3 attempts")
+	assert.Equal(s.T(), 3, fake.pingCount)
+	assert.Equal(s.T(), 2, sleepCount)
+}
+
+// Test_Retry_ZeroRetries verifies that a zero retry count makes a single
+// attempt without sleeping.
+func (s *ConnectionTestSuite) Test_Retry_ZeroRetries() {
+	fake := NewFakeDB("driver", "dsn")
+	fake.pingErr = errors.New("ping failed")
+	sleepCount := 0
+
+	_, err := connect(
+		s.cfg,
+		func(driver string, dsn string) (types.DB, error) { return fake, nil },
+		"dsn",
+		func(d time.Duration) { sleepCount++ },
+	)
+	require.Error(s.T(), err)
+	assert.NotContains(s.T(), err.Error(), "attempts")
+	assert.Equal(s.T(), 1, fake.pingCount)
+	assert.Equal(s.T(), 0, sleepCount)
+}
@@ -2,11 +2,19 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/pureapi/pureapi-core/database/types"
 )
 
+// ErrNotFound is returned when a query expected to return a row returns none.
+// Errors wrapping it also wrap the original driver error, so both
+// errors.Is(err, ErrNotFound) and errors.Is(err, sql.ErrNoRows) hold.
+var ErrNotFound = errors.New("not found")
+
 // Exec prepares and executes a query with parameters, returning the Result.
 //
 // Parameters:
@@ -25,10 +33,11 @@ func Exec(
 	query string,
 	parameters []any,
 	errorChecker types.ErrorChecker,
-) (types.Result, error) {
+) (_ types.Result, err error) {
 	if preparer == nil {
 		return nil, fmt.Errorf("Exec: preparer is nil")
 	}
+	defer observeQuery(ctx, query, parameters)(&err)
 	result, err := doExec(ctx, preparer, query, parameters)
 	if err != nil {
 		if errorChecker == nil {
@@ -39,6 +48,71 @@ func Exec(
 	return result, nil
 }
 
+// Statement is a query and its parameters executed by ExecMany.
+type Statement struct {
+	Query  string
+	Params []any
+}
+
+// ExecMany prepares and executes the statements in order and returns the
+// sum of the rows they affected. It stops at the first failing statement and
+// returns the rows affected by the statements that succeeded before it,
+// together with the error. The statements are not run in a transaction by
+// ExecMany; pass a transaction as the preparer, for example within WithTx, to
+// make them atomic.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - preparer: The preparer to use for the statements.
+//   - statements: The statements to execute.
+//   - errorChecker: An optional ErrorChecker to check for errors.
+//
+// Returns:
+//   - int64: The total number of rows affected, up to the failing statement.
+//   - error: An error if any statement fails.
+func ExecMany(
+	ctx context.Context,
+	preparer types.Preparer,
+	statements []Statement,
+	errorChecker types.ErrorChecker,
+) (int64, error) {
+	if preparer == nil {
+		return 0, fmt.Errorf("ExecMany: preparer is nil")
+	}
+	var total int64
+	for i, statement := range statements {
+		affected, err := execStatement(ctx, preparer, statement, errorChecker)
+		if err != nil {
+			return total, fmt.Errorf("ExecMany: statement %d: %w", i, err)
+		}
+		total += affected
+	}
+	return total, nil
+}
+
+// execStatement executes a statement of ExecMany and returns the number of
+// rows it affected.
+func execStatement(
+	ctx context.Context,
+	preparer types.Preparer,
+	statement Statement,
+	errorChecker types.ErrorChecker,
+) (_ int64, err error) {
+	defer observeQuery(ctx, statement.Query, statement.Params)(&err)
+	result, err := doExec(ctx, preparer, statement.Query, statement.Params)
+	if err == nil {
+		var affected int64
+		affected, err = result.RowsAffected()
+		if err == nil {
+			return affected, nil
+		}
+	}
+	if errorChecker != nil {
+		err = errorChecker.Check(err)
+	}
+	return 0, err
+}
+
 // Query prepares and executes a query that returns rows. The caller is
 // responsible for closing both the returned rows and statement.
 //
@@ -59,10 +133,11 @@ func Query(
 	query string,
 	parameters []any,
 	errorChecker types.ErrorChecker,
-) (types.Rows, types.Stmt, error) {
+) (_ types.Rows, _ types.Stmt, err error) {
 	if preparer == nil {
 		return nil, nil, fmt.Errorf("Query: preparer is nil")
 	}
+	defer observeQuery(ctx, query, parameters)(&err)
 	rows, stmt, err := doQuery(ctx, preparer, query, parameters)
 	if err != nil {
 		if errorChecker == nil {
@@ -91,10 +166,11 @@ func ExecRaw(
 	query string,
 	parameters []any,
 	errorChecker types.ErrorChecker,
-) (types.Result, error) {
+) (_ types.Result, err error) {
 	if db == nil {
 		return nil, fmt.Errorf("ExecRaw: db is nil")
 	}
+	defer observeQuery(ctx, query, parameters)(&err)
 	result, err := doExecRaw(ctx, db, query, parameters)
 	if err != nil {
 		if errorChecker == nil {
@@ -124,10 +200,11 @@ func QueryRaw(
 	query string,
 	parameters []any,
 	errorChecker types.ErrorChecker,
-) (types.Rows, error) {
+) (_ types.Rows, err error) {
 	if db == nil {
 		return nil, fmt.Errorf("QueryRaw: db is nil")
 	}
+	defer observeQuery(ctx, query, parameters)(&err)
 	rows, err := doQueryRaw(ctx, db, query, parameters)
 	if err != nil {
 		if errorChecker == nil {
@@ -161,12 +238,16 @@ func QuerySingleValue[T any](
 	parameters []any,
 	errorChecker types.ErrorChecker,
 	factoryFn func() T,
-) (T, error) {
+) (_ T, err error) {
 	var zero T
 	if preparer == nil {
 		return zero, fmt.Errorf("QuerySingleValue: preparer is nil")
 	}
-	stmt, err := preparer.Prepare(query)
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	defer observeQuery(ctx, query, parameters)(&err)
+	stmt, err := preparer.PrepareContext(ctx, query)
 	if err != nil {
 		if errorChecker == nil {
 			return zero, err
@@ -175,7 +256,7 @@ func QuerySingleValue[T any](
 	}
 	defer stmt.Close()
 
-	row := stmt.QueryRow(parameters...)
+	row := stmt.QueryRowContext(ctx, parameters...)
 	result, err := RowToAny(ctx, row, factoryFn)
 	if err != nil {
 		if errorChecker != nil {
@@ -186,8 +267,65 @@ func QuerySingleValue[T any](
 	return result, nil
 }
 
+// QueryNullableValue executes a query that is expected to return a single
+// scalar value that may be NULL, such as MAX over an empty table. The value
+// is scanned through sql.Null, so T is the value type rather than a pointer.
+// If the query returns no rows, the error wraps ErrNotFound before it is
+// passed to the error checker.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - preparer: The preparer to use for the query.
+//   - query: The SQL query to execute.
+//   - parameters: The query parameters.
+//   - errorChecker: An optional ErrorChecker to check for errors.
+//
+// Returns:
+//   - T: The scanned value, or the zero value if it is NULL.
+//   - bool: False if the value is NULL.
+//   - error: An error if the query or scan fails.
+func QueryNullableValue[T any](
+	ctx context.Context,
+	preparer types.Preparer,
+	query string,
+	parameters []any,
+	errorChecker types.ErrorChecker,
+) (_ T, _ bool, err error) {
+	var zero T
+	if preparer == nil {
+		return zero, false, fmt.Errorf("QueryNullableValue: preparer is nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return zero, false, err
+	}
+	defer observeQuery(ctx, query, parameters)(&err)
+	stmt, err := preparer.PrepareContext(ctx, query)
+	if err != nil {
+		if errorChecker == nil {
+			return zero, false, err
+		}
+		return zero, false, errorChecker.Check(err)
+	}
+	defer stmt.Close()
+	var value sql.Null[T]
+	row := stmt.QueryRowContext(ctx, parameters...)
+	err = row.Scan(&value)
+	if err == nil {
+		err = row.Err()
+	}
+	if err != nil {
+		err = notFoundError(err)
+		if errorChecker == nil {
+			return zero, false, err
+		}
+		return zero, false, errorChecker.Check(err)
+	}
+	return value.V, value.Valid, nil
+}
+
 // QuerySingleEntity executes a query and scans a single entity of type T,
-// handling statement and row closures internally.
+// handling statement and row closures internally. If the query returns no
+// rows, the error wraps ErrNotFound before it is passed to the error checker.
 //
 // Parameters:
 //   - ctx: Context to use.
@@ -207,12 +345,16 @@ func QuerySingleEntity[Entity types.Getter](
 	parameters []any,
 	errorChecker types.ErrorChecker,
 	factoryFn func() Entity,
-) (Entity, error) {
+) (_ Entity, err error) {
 	var zero Entity
 	if preparer == nil {
 		return zero, fmt.Errorf("QuerySingleEntity: preparer is nil")
 	}
-	stmt, err := preparer.Prepare(query)
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	defer observeQuery(ctx, query, parameters)(&err)
+	stmt, err := preparer.PrepareContext(ctx, query)
 	if err != nil {
 		if errorChecker == nil {
 			return zero, err
@@ -220,7 +362,9 @@ func QuerySingleEntity[Entity types.Getter](
 		return zero, errorChecker.Check(err)
 	}
 	defer stmt.Close()
-	entity, err := RowToEntity(ctx, stmt.QueryRow(parameters...), factoryFn)
+	entity, err := RowToEntity(
+		ctx, stmt.QueryRowContext(ctx, parameters...), factoryFn,
+	)
 	if err != nil {
 		if errorChecker == nil {
 			return zero, err
@@ -230,6 +374,61 @@ func QuerySingleEntity[Entity types.Getter](
 	return entity, nil
 }
 
+// QuerySingleAny executes a query and scans a single row into a value of
+// type T, handling statement and row closures internally. It is the
+// counterpart of QuerySingleEntity for types that do not implement Getter,
+// such as the results of aggregate queries. If the query returns no rows,
+// the error wraps ErrNotFound before it is passed to the error checker.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - preparer: The preparer to use for the query.
+//   - query: The SQL query to execute.
+//   - parameters: The query parameters.
+//   - errorChecker: An optional ErrorChecker to check for errors.
+//   - factoryFn: A function that returns a new instance of T
+//     (typically a pointer).
+//
+// Returns:
+//   - T: The value scanned from the query.
+//   - error: An error if the query fails.
+func QuerySingleAny[T any](
+	ctx context.Context,
+	preparer types.Preparer,
+	query string,
+	parameters []any,
+	errorChecker types.ErrorChecker,
+	factoryFn func() T,
+) (_ T, err error) {
+	var zero T
+	if preparer == nil {
+		return zero, fmt.Errorf("QuerySingleAny: preparer is nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	defer observeQuery(ctx, query, parameters)(&err)
+	stmt, err := preparer.PrepareContext(ctx, query)
+	if err != nil {
+		if errorChecker == nil {
+			return zero, err
+		}
+		return zero, errorChecker.Check(err)
+	}
+	defer stmt.Close()
+	result, err := RowToAny(
+		ctx, stmt.QueryRowContext(ctx, parameters...), factoryFn,
+	)
+	if err != nil {
+		err = notFoundError(err)
+		if errorChecker == nil {
+			return zero, err
+		}
+		return zero, errorChecker.Check(err)
+	}
+	return result, nil
+}
+
 // QueryEntities executes a query and scans all entities of type T,
 // handling statement and row closures internally.
 //
@@ -264,7 +463,8 @@ func QueryEntities[Entity types.Getter](
 	return RowsToEntities(ctx, rows, factoryFn)
 }
 
-// RowToEntity scans a single row into a new entity.
+// RowToEntity scans a single row into a new entity. If the row is empty, the
+// returned error wraps ErrNotFound.
 //
 // Parameters:
 //   - ctx: Context to use.
@@ -280,7 +480,7 @@ func RowToEntity[T types.Getter](
 	var zero T
 	entity := factoryFn()
 	if err := entity.ScanRow(row); err != nil {
-		return zero, err
+		return zero, notFoundError(err)
 	}
 	if err := row.Err(); err != nil {
 		return zero, err
@@ -372,48 +572,183 @@ func RowsToEntities[T types.Getter](
 	return results, nil
 }
 
-// doExec executes a query with parameters.
+// RowsToMaps scans all rows into maps keyed by column name. It is meant for
+// queries without a matching entity type. Byte slices are converted to
+// strings and NULL values are stored as nil.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - rows: The rows to scan.
+//
+// Returns:
+//   - []map[string]any: A slice of maps scanned from the rows.
+//   - error: An error if reading the columns or scanning fails.
+func RowsToMaps(
+	_ context.Context, rows types.Rows,
+) ([]map[string]any, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	results := []map[string]any{}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result := make(map[string]any, len(columns))
+		for i, column := range columns {
+			switch value := values[i].(type) {
+			case []byte:
+				result[column] = string(value)
+			default:
+				result[column] = value
+			}
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// StreamEntities scans rows one at a time and passes each entity to fn
+// without buffering the result set. Iteration stops at the first error
+// returned by fn, by scanning or by the context. The rows are closed before
+// returning.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - rows: The rows to scan.
+//   - factoryFn: A function that returns a new instance of T.
+//   - fn: The function to call for each entity.
+//
+// Returns:
+//   - error: An error if scanning, fn or the rows fail.
+func StreamEntities[T types.Getter](
+	ctx context.Context,
+	rows types.Rows,
+	factoryFn func() T,
+	fn func(entity T) error,
+) error {
+	defer rows.Close()
+	for entity, err := range EntitiesSeq(ctx, rows, factoryFn) {
+		if err != nil {
+			return err
+		}
+		if err := fn(entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EntitiesSeq returns an iterator that scans rows one at a time. If scanning,
+// the context or the rows fail, the error is yielded with a zero entity and
+// iteration ends. The rows are closed when iteration ends, including when the
+// caller breaks out of the loop early.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - rows: The rows to scan.
+//   - factoryFn: A function that returns a new instance of T.
+//
+// Returns:
+//   - iter.Seq2[T, error]: An iterator of entities and errors.
+func EntitiesSeq[T types.Getter](
+	ctx context.Context, rows types.Rows, factoryFn func() T,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer rows.Close()
+		var zero T
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			entity := factoryFn()
+			if err := entity.ScanRow(rows); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(entity, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// notFoundError wraps err with ErrNotFound if it is sql.ErrNoRows.
+func notFoundError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
+
+// doExec executes a query with parameters. It returns the context error
+// without touching the database if the context is already done.
 func doExec(
-	_ context.Context,
+	ctx context.Context,
 	preparer types.Preparer,
 	query string,
 	parameters []any,
 ) (types.Result, error) {
-	stmt, err := preparer.Prepare(query)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	stmt, err := preparer.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
-	result, err := stmt.Exec(parameters...)
+	result, err := stmt.ExecContext(ctx, parameters...)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-// doExecRaw executes a query directly on the DB without preparation.
+// doExecRaw executes a query directly on the DB without preparation. It
+// returns the context error without touching the database if the context is
+// already done.
 func doExecRaw(
-	_ context.Context, db types.DB, query string, parameters []any,
+	ctx context.Context, db types.DB, query string, parameters []any,
 ) (types.Result, error) {
-	result, err := db.Exec(query, parameters...)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := db.ExecContext(ctx, query, parameters...)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-// doQuery executes a query with parameters.
+// doQuery executes a query with parameters. It returns the context error
+// without touching the database if the context is already done.
 func doQuery(
-	_ context.Context,
+	ctx context.Context,
 	preparer types.Preparer,
 	query string,
 	parameters []any,
 ) (types.Rows, types.Stmt, error) {
-	stmt, err := preparer.Prepare(query)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	stmt, err := preparer.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, nil, err
 	}
-	rows, err := stmt.Query(parameters...)
+	rows, err := stmt.QueryContext(ctx, parameters...)
 	if err != nil {
 		if closeErr := stmt.Close(); closeErr != nil {
 			return nil, nil, fmt.Errorf(
@@ -427,11 +762,16 @@ func doQuery(
 	return rows, stmt, nil
 }
 
-// doQueryRaw executes a query directly on the DB without preparation.
+// doQueryRaw executes a query directly on the DB without preparation. It
+// returns the context error without touching the database if the context is
+// already done.
 func doQueryRaw(
-	_ context.Context, db types.DB, query string, parameters []any,
+	ctx context.Context, db types.DB, query string, parameters []any,
 ) (types.Rows, error) {
-	rows, err := db.Query(query, parameters...)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, parameters...)
 	if err != nil {
 		return nil, err
 	}
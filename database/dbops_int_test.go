@@ -441,3 +441,44 @@ func (s *DBOpsIntTestSuite) Test_RowsToEntities() {
 		require.True(s.T(), e.ID > 0)
 	}
 }
+
+// Test_RowsToMaps verifies that RowsToMaps scans rows of mixed column types
+// into maps and stores NULL values as nil.
+func (s *DBOpsIntTestSuite) Test_RowsToMaps() {
+	createStmt := `
+		CREATE TABLE test_maps (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			score REAL,
+			data BLOB
+		);
+	`
+	_, err := s.db.Exec(createStmt)
+	require.NoError(s.T(), err)
+	_, err = s.db.Exec(
+		"INSERT INTO test_maps (name, score, data) VALUES (?, ?, ?)",
+		"Alice", 1.5, []byte("raw"),
+	)
+	require.NoError(s.T(), err)
+	_, err = s.db.Exec(
+		"INSERT INTO test_maps (name, score, data) VALUES (?, ?, ?)",
+		nil, nil, nil,
+	)
+	require.NoError(s.T(), err)
+
+	rows, err := s.db.Query(
+		"SELECT id, name, score, data FROM test_maps ORDER BY id ASC",
+	)
+	require.NoError(s.T(), err)
+	defer rows.Close()
+
+	results, err := RowsToMaps(s.ctx, rows)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 2)
+	require.Equal(s.T(), map[string]any{
+		"id": int64(1), "name": "Alice", "score": 1.5, "data": "raw",
+	}, results[0])
+	require.Equal(s.T(), map[string]any{
+		"id": int64(2), "name": nil, "score": nil, "data": nil,
+	}, results[1])
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -25,6 +26,12 @@ func (fp *fakePreparer) Prepare(query string) (types.Stmt, error) {
 	return nil, nil
 }
 
+func (fp *fakePreparer) PrepareContext(
+	_ context.Context, query string,
+) (types.Stmt, error) {
+	return fp.Prepare(query)
+}
+
 // fakeStmt implements types.Stmt.
 type fakeStmt struct {
 	execFunc     func(args ...any) (types.Result, error)
@@ -54,6 +61,22 @@ func (fs *fakeStmt) QueryRow(args ...any) types.Row {
 	return nil
 }
 
+func (fs *fakeStmt) ExecContext(
+	_ context.Context, args ...any,
+) (types.Result, error) {
+	return fs.Exec(args...)
+}
+
+func (fs *fakeStmt) QueryContext(
+	_ context.Context, args ...any,
+) (types.Rows, error) {
+	return fs.Query(args...)
+}
+
+func (fs *fakeStmt) QueryRowContext(_ context.Context, args ...any) types.Row {
+	return fs.QueryRow(args...)
+}
+
 func (fs *fakeStmt) Close() error {
 	if fs.closeFunc != nil {
 		return fs.closeFunc()
@@ -77,10 +100,13 @@ func (fr *fakeResult) RowsAffected() (int64, error) {
 
 // fakeRows implements types.Rows.
 type fakeRows struct {
-	current   int
-	total     int
-	scanFunc  func(dest ...any) error
-	returnErr error
+	current    int
+	total      int
+	scanFunc   func(dest ...any) error
+	returnErr  error
+	closed     bool
+	columns    []string
+	columnsErr error
 }
 
 func (fr *fakeRows) Next() bool {
@@ -99,6 +125,7 @@ func (fr *fakeRows) Scan(dest ...any) error {
 }
 
 func (fr *fakeRows) Close() error {
+	fr.closed = true
 	return nil
 }
 
@@ -106,6 +133,10 @@ func (fr *fakeRows) Err() error {
 	return fr.returnErr
 }
 
+func (fr *fakeRows) Columns() ([]string, error) {
+	return fr.columns, fr.columnsErr
+}
+
 // fakeRow implements types.Row.
 type fakeRow struct {
 	scanFunc func(dest ...any) error
@@ -128,6 +159,7 @@ type fakeDB struct {
 	execFunc  func(query string, args ...any) (types.Result, error)
 	queryFunc func(query string, args ...any) (types.Rows, error)
 	closeFunc func() error
+	stats     sql.DBStats
 }
 
 func (fdb *fakeDB) Exec(query string, args ...any) (types.Result, error) {
@@ -149,6 +181,24 @@ func (fdb *fakeDB) QueryRow(query string, args ...any) types.Row {
 	return nil
 }
 
+func (fdb *fakeDB) ExecContext(
+	_ context.Context, query string, args ...any,
+) (types.Result, error) {
+	return fdb.Exec(query, args...)
+}
+
+func (fdb *fakeDB) QueryContext(
+	_ context.Context, query string, args ...any,
+) (types.Rows, error) {
+	return fdb.Query(query, args...)
+}
+
+func (fdb *fakeDB) QueryRowContext(
+	_ context.Context, query string, args ...any,
+) types.Row {
+	return fdb.QueryRow(query, args...)
+}
+
 func (fdb *fakeDB) Close() error {
 	if fdb.closeFunc != nil {
 		return fdb.closeFunc()
@@ -156,14 +206,23 @@ func (fdb *fakeDB) Close() error {
 	return nil
 }
 
-func (fdb *fakeDB) Ping() error                        { return nil }
+func (fdb *fakeDB) Ping() error { return nil }
+func (fdb *fakeDB) PingContext(ctx context.Context) error {
+	return ctx.Err()
+}
 func (fdb *fakeDB) SetConnMaxLifetime(d time.Duration) {}
 func (fdb *fakeDB) SetConnMaxIdleTime(d time.Duration) {}
 func (fdb *fakeDB) SetMaxOpenConns(n int)              {}
 func (fdb *fakeDB) SetMaxIdleConns(n int)              {}
+func (fdb *fakeDB) Stats() sql.DBStats                 { return fdb.stats }
 func (fdb *fakeDB) Prepare(query string) (types.Stmt, error) {
 	return nil, nil
 }
+func (fdb *fakeDB) PrepareContext(
+	_ context.Context, query string,
+) (types.Stmt, error) {
+	return nil, nil
+}
 func (fdb *fakeDB) BeginTx(ctx context.Context,
 	opts *sql.TxOptions) (types.Tx, error) {
 	return nil, errors.New("not implemented")
@@ -181,6 +240,15 @@ func (fec *fakeErrorChecker) Check(err error) error {
 	return errors.New(fec.prefix + err.Error())
 }
 
+// funcErrorChecker implements types.ErrorChecker with a custom function.
+type funcErrorChecker struct {
+	checkFunc func(err error) error
+}
+
+func (fec *funcErrorChecker) Check(err error) error {
+	return fec.checkFunc(err)
+}
+
 // fakeEntity is used for testing QuerySingleEntity.
 type fakeEntity struct {
 	Value int
@@ -266,6 +334,72 @@ func (s *DBOpsTestSuite) TestExec_ErrorChecker() {
 	assert.Nil(s.T(), result)
 }
 
+// TestExecMany_Success tests that ExecMany executes the statements in order
+// and sums the rows affected.
+func (s *DBOpsTestSuite) TestExecMany_Success() {
+	var executed []string
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return &fakeStmt{
+				execFunc: func(args ...any) (types.Result, error) {
+					executed = append(executed, query)
+					return &fakeResult{rowsAffected: 2}, nil
+				},
+				closeFunc: func() error { return nil },
+			}, nil
+		},
+	}
+	total, err := ExecMany(s.ctx, fakePrep, []Statement{
+		{Query: "INSERT", Params: []any{1}},
+		{Query: "UPDATE", Params: []any{2}},
+		{Query: "DELETE"},
+	}, nil)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(6), total)
+	assert.Equal(s.T(), []string{"INSERT", "UPDATE", "DELETE"}, executed)
+}
+
+// TestExecMany_StopsOnError tests that ExecMany stops at the first failing
+// statement, passes the error to the error checker and returns the rows
+// affected before the failure.
+func (s *DBOpsTestSuite) TestExecMany_StopsOnError() {
+	var executed []string
+	execErr := errors.New("exec failed")
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return &fakeStmt{
+				execFunc: func(args ...any) (types.Result, error) {
+					executed = append(executed, query)
+					if query == "UPDATE" {
+						return nil, execErr
+					}
+					return &fakeResult{rowsAffected: 1}, nil
+				},
+				closeFunc: func() error { return nil },
+			}, nil
+		},
+	}
+	total, err := ExecMany(s.ctx, fakePrep, []Statement{
+		{Query: "INSERT"},
+		{Query: "UPDATE"},
+		{Query: "DELETE"},
+	}, s.errorChecker)
+	require.Error(s.T(), err)
+	assert.Equal(
+		s.T(), "ExecMany: statement 1: checked: exec failed", err.Error(),
+	)
+	assert.Equal(s.T(), int64(1), total)
+	assert.Equal(s.T(), []string{"INSERT", "UPDATE"}, executed)
+}
+
+// TestExecMany_NilPreparer tests that ExecMany returns an error if the
+// preparer is nil.
+func (s *DBOpsTestSuite) TestExecMany_NilPreparer() {
+	_, err := ExecMany(s.ctx, nil, []Statement{{Query: "DELETE"}}, nil)
+	require.Error(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "ExecMany: preparer is nil")
+}
+
 // TestQuery_NilPreparer tests that Query returns an error if the preparer is
 // nil.
 func (s *DBOpsTestSuite) TestQuery_NilPreparer() {
@@ -438,6 +572,71 @@ func (s *DBOpsTestSuite) TestQuerySingleValue_Success() {
 	assert.Equal(s.T(), 55, *result)
 }
 
+// nullableValuePreparer returns a preparer whose row scans src into the
+// destination through its sql.Scanner implementation.
+func nullableValuePreparer(src any) *fakePreparer {
+	return &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return &fakeStmt{
+				queryRowFunc: func(args ...any) types.Row {
+					return &fakeRow{
+						scanFunc: func(dest ...any) error {
+							return dest[0].(sql.Scanner).Scan(src)
+						},
+					}
+				},
+				closeFunc: func() error { return nil },
+			}, nil
+		},
+	}
+}
+
+// TestQueryNullableValue_Value tests that QueryNullableValue returns a
+// present value.
+func (s *DBOpsTestSuite) TestQueryNullableValue_Value() {
+	value, ok, err := QueryNullableValue[int64](
+		s.ctx, nullableValuePreparer(int64(9)), "SELECT MAX(x)", nil, nil,
+	)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), int64(9), value)
+}
+
+// TestQueryNullableValue_Null tests that QueryNullableValue returns the zero
+// value and false for NULL.
+func (s *DBOpsTestSuite) TestQueryNullableValue_Null() {
+	value, ok, err := QueryNullableValue[int64](
+		s.ctx, nullableValuePreparer(nil), "SELECT MAX(x)", nil, nil,
+	)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), ok)
+	assert.Equal(s.T(), int64(0), value)
+}
+
+// TestQueryNullableValue_NotFound tests that QueryNullableValue returns an
+// error wrapping ErrNotFound if the query returns no rows.
+func (s *DBOpsTestSuite) TestQueryNullableValue_NotFound() {
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return &fakeStmt{
+				queryRowFunc: func(args ...any) types.Row {
+					return &fakeRow{
+						scanFunc: func(dest ...any) error {
+							return sql.ErrNoRows
+						},
+					}
+				},
+				closeFunc: func() error { return nil },
+			}, nil
+		},
+	}
+	_, ok, err := QueryNullableValue[int64](
+		s.ctx, fakePrep, "SELECT x", nil, nil,
+	)
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.False(s.T(), ok)
+}
+
 // TestQuerySingleEntity_NilPreparer tests that QuerySingleEntity returns an
 // error if the preparer is nil.
 func (s *DBOpsTestSuite) TestQuerySingleEntity_NilPreparer() {
@@ -490,6 +689,158 @@ func (s *DBOpsTestSuite) TestQuerySingleEntity_Success() {
 	assert.Equal(s.T(), 77, entity.Value)
 }
 
+// TestQuerySingleEntity_NotFound tests that QuerySingleEntity returns an
+// error wrapping ErrNotFound and sql.ErrNoRows if the query returns no rows.
+func (s *DBOpsTestSuite) TestQuerySingleEntity_NotFound() {
+	fakeStmt := &fakeStmt{
+		queryRowFunc: func(args ...any) types.Row {
+			return &fakeRow{
+				scanFunc: func(dest ...any) error { return sql.ErrNoRows },
+			}
+		},
+		closeFunc: func() error { return nil },
+	}
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return fakeStmt, nil
+		},
+	}
+	_, err := QuerySingleEntity(
+		s.ctx,
+		fakePrep,
+		"SELECT val",
+		nil,
+		nil,
+		func() *fakeEntity { return new(fakeEntity) },
+	)
+	require.Error(s.T(), err)
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+}
+
+// TestQuerySingleEntity_NotFoundErrorChecker tests that the error checker
+// receives the ErrNotFound error and can wrap it.
+func (s *DBOpsTestSuite) TestQuerySingleEntity_NotFoundErrorChecker() {
+	fakeStmt := &fakeStmt{
+		queryRowFunc: func(args ...any) types.Row {
+			return &fakeRow{
+				scanFunc: func(dest ...any) error { return sql.ErrNoRows },
+			}
+		},
+		closeFunc: func() error { return nil },
+	}
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return fakeStmt, nil
+		},
+	}
+	var checked error
+	checker := &funcErrorChecker{
+		checkFunc: func(err error) error {
+			checked = err
+			return fmt.Errorf("checked: %w", err)
+		},
+	}
+	_, err := QuerySingleEntity(
+		s.ctx,
+		fakePrep,
+		"SELECT val",
+		nil,
+		checker,
+		func() *fakeEntity { return new(fakeEntity) },
+	)
+	assert.ErrorIs(s.T(), checked, ErrNotFound)
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.Contains(s.T(), err.Error(), "checked: ")
+}
+
+// TestQuerySingleAny_Success tests that QuerySingleAny returns the scanned
+// value if the query returns a row.
+func (s *DBOpsTestSuite) TestQuerySingleAny_Success() {
+	fakeStmt := &fakeStmt{
+		queryRowFunc: func(args ...any) types.Row {
+			return &fakeRow{
+				scanFunc: func(dest ...any) error {
+					*dest[0].(*int64) = 12
+					return nil
+				},
+			}
+		},
+		closeFunc: func() error { return nil },
+	}
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return fakeStmt, nil
+		},
+	}
+	result, err := QuerySingleAny(
+		s.ctx,
+		fakePrep,
+		"SELECT SUM(quantity) FROM orders",
+		nil,
+		nil,
+		func() *int64 { return new(int64) },
+	)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(12), *result)
+}
+
+// TestQuerySingleAny_NotFound tests that QuerySingleAny returns an error
+// wrapping ErrNotFound if the query returns no rows.
+func (s *DBOpsTestSuite) TestQuerySingleAny_NotFound() {
+	fakeStmt := &fakeStmt{
+		queryRowFunc: func(args ...any) types.Row {
+			return &fakeRow{
+				scanFunc: func(dest ...any) error { return sql.ErrNoRows },
+			}
+		},
+		closeFunc: func() error { return nil },
+	}
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			return fakeStmt, nil
+		},
+	}
+	result, err := QuerySingleAny(
+		s.ctx,
+		fakePrep,
+		"SELECT SUM(quantity) FROM orders",
+		nil,
+		nil,
+		func() *int64 { return new(int64) },
+	)
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+	assert.Nil(s.T(), result)
+}
+
+// TestQuerySingleAny_NilPreparer tests that QuerySingleAny returns an error
+// if the preparer is nil.
+func (s *DBOpsTestSuite) TestQuerySingleAny_NilPreparer() {
+	_, err := QuerySingleAny(
+		s.ctx, nil, "SELECT 1", nil, nil, func() *int { return new(int) },
+	)
+	require.Error(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "QuerySingleAny: preparer is nil")
+}
+
+// TestRowToEntity_NotFound tests that RowToEntity translates sql.ErrNoRows
+// into ErrNotFound and leaves other errors untouched.
+func (s *DBOpsTestSuite) TestRowToEntity_NotFound() {
+	_, err := RowToEntity(s.ctx, &fakeRow{
+		scanFunc: func(dest ...any) error { return sql.ErrNoRows },
+	}, func() *fakeEntity { return new(fakeEntity) })
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+
+	scanErr := errors.New("scan error")
+	_, err = RowToEntity(s.ctx, &fakeRow{
+		scanFunc: func(dest ...any) error { return scanErr },
+	}, func() *fakeEntity { return new(fakeEntity) })
+	assert.ErrorIs(s.T(), err, scanErr)
+	assert.NotErrorIs(s.T(), err, ErrNotFound)
+}
+
 // TestQueryEntities_NilPreparer tests that QueryEntities returns an error if
 // the preparer is nil.
 func (s *DBOpsTestSuite) TestQueryEntities_Success() {
@@ -536,3 +887,184 @@ func (s *DBOpsTestSuite) TestQueryEntities_Success() {
 	assert.Equal(s.T(), &fakeEntity{Value: 10}, entities[0])
 	assert.Equal(s.T(), &fakeEntity{Value: 20}, entities[1])
 }
+
+// TestCancelledContext tests that Exec, Query, ExecRaw and QueryRaw return
+// context.Canceled without touching the database when the context is already
+// cancelled.
+func (s *DBOpsTestSuite) TestCancelledContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	prepareCalled := false
+	fakePrep := &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			prepareCalled = true
+			return &fakeStmt{}, nil
+		},
+	}
+	dbCalled := false
+	fakeDBObj := &fakeDB{
+		execFunc: func(query string, args ...any) (types.Result, error) {
+			dbCalled = true
+			return &fakeResult{}, nil
+		},
+		queryFunc: func(query string, args ...any) (types.Rows, error) {
+			dbCalled = true
+			return &fakeRows{}, nil
+		},
+	}
+
+	_, err := Exec(ctx, fakePrep, "UPDATE t SET a=?", []any{1}, nil)
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	_, _, err = Query(ctx, fakePrep, "SELECT a FROM t", nil, nil)
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	_, err = ExecRaw(ctx, fakeDBObj, "UPDATE t SET a=?", []any{1}, nil)
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	_, err = QueryRaw(ctx, fakeDBObj, "SELECT a FROM t", nil, nil)
+	assert.ErrorIs(s.T(), err, context.Canceled)
+
+	assert.False(s.T(), prepareCalled, "Prepare should not be called")
+	assert.False(s.T(), dbCalled, "DB should not be called")
+}
+
+// countingScan returns a scan function that writes an increasing counter.
+func countingScan() func(dest ...any) error {
+	n := 0
+	return func(dest ...any) error {
+		n++
+		if ptr, ok := dest[0].(*int); ok {
+			*ptr = n
+		}
+		return nil
+	}
+}
+
+// TestStreamEntities_Success tests that StreamEntities calls fn for each row
+// and closes the rows.
+func (s *DBOpsTestSuite) TestStreamEntities_Success() {
+	rows := &fakeRows{total: 3, scanFunc: countingScan()}
+	var values []int
+	err := StreamEntities(
+		s.ctx,
+		rows,
+		func() *fakeEntity { return new(fakeEntity) },
+		func(e *fakeEntity) error {
+			values = append(values, e.Value)
+			return nil
+		},
+	)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []int{1, 2, 3}, values)
+	assert.True(s.T(), rows.closed)
+}
+
+// TestStreamEntities_StopsOnFnError tests that StreamEntities stops at the
+// first error returned by fn.
+func (s *DBOpsTestSuite) TestStreamEntities_StopsOnFnError() {
+	rows := &fakeRows{total: 5, scanFunc: countingScan()}
+	fnErr := errors.New("fn error")
+	calls := 0
+	err := StreamEntities(
+		s.ctx,
+		rows,
+		func() *fakeEntity { return new(fakeEntity) },
+		func(e *fakeEntity) error {
+			calls++
+			if e.Value == 2 {
+				return fnErr
+			}
+			return nil
+		},
+	)
+	assert.ErrorIs(s.T(), err, fnErr)
+	assert.Equal(s.T(), 2, calls)
+	assert.True(s.T(), rows.closed)
+}
+
+// TestStreamEntities_RowsErr tests that StreamEntities surfaces rows.Err after
+// iteration.
+func (s *DBOpsTestSuite) TestStreamEntities_RowsErr() {
+	rowsErr := errors.New("rows error")
+	rows := &fakeRows{total: 2, scanFunc: countingScan(), returnErr: rowsErr}
+	calls := 0
+	err := StreamEntities(
+		s.ctx,
+		rows,
+		func() *fakeEntity { return new(fakeEntity) },
+		func(e *fakeEntity) error {
+			calls++
+			return nil
+		},
+	)
+	assert.ErrorIs(s.T(), err, rowsErr)
+	assert.Equal(s.T(), 2, calls)
+}
+
+// TestEntitiesSeq tests that EntitiesSeq yields entities, yields scan errors
+// and closes the rows when the caller breaks early.
+func (s *DBOpsTestSuite) TestEntitiesSeq() {
+	rows := &fakeRows{total: 3, scanFunc: countingScan()}
+	var values []int
+	for e, err := range EntitiesSeq(
+		s.ctx, rows, func() *fakeEntity { return new(fakeEntity) },
+	) {
+		require.NoError(s.T(), err)
+		values = append(values, e.Value)
+	}
+	assert.Equal(s.T(), []int{1, 2, 3}, values)
+	assert.True(s.T(), rows.closed)
+
+	rows = &fakeRows{total: 3, scanFunc: countingScan()}
+	for range EntitiesSeq(
+		s.ctx, rows, func() *fakeEntity { return new(fakeEntity) },
+	) {
+		break
+	}
+	assert.True(s.T(), rows.closed)
+	assert.Equal(s.T(), 1, rows.current)
+
+	scanErr := errors.New("scan error")
+	rows = &fakeRows{
+		total:    3,
+		scanFunc: func(dest ...any) error { return scanErr },
+	}
+	var errs []error
+	for _, err := range EntitiesSeq(
+		s.ctx, rows, func() *fakeEntity { return new(fakeEntity) },
+	) {
+		errs = append(errs, err)
+	}
+	require.Len(s.T(), errs, 1)
+	assert.ErrorIs(s.T(), errs[0], scanErr)
+}
+
+// TestRowsToMaps tests that RowsToMaps keys values by column, converts byte
+// slices to strings and surfaces column and scan errors.
+func (s *DBOpsTestSuite) TestRowsToMaps() {
+	rows := &fakeRows{
+		total:   1,
+		columns: []string{"id", "name", "note"},
+		scanFunc: func(dest ...any) error {
+			*dest[0].(*any) = int64(7)
+			*dest[1].(*any) = []byte("Alice")
+			*dest[2].(*any) = nil
+			return nil
+		},
+	}
+	results, err := RowsToMaps(s.ctx, rows)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []map[string]any{
+		{"id": int64(7), "name": "Alice", "note": nil},
+	}, results)
+
+	columnsErr := errors.New("columns error")
+	_, err = RowsToMaps(s.ctx, &fakeRows{columnsErr: columnsErr})
+	assert.ErrorIs(s.T(), err, columnsErr)
+
+	scanErr := errors.New("scan error")
+	_, err = RowsToMaps(s.ctx, &fakeRows{
+		total:    1,
+		columns:  []string{"id"},
+		scanFunc: func(dest ...any) error { return scanErr },
+	})
+	assert.ErrorIs(s.T(), err, scanErr)
+}
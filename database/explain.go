@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pureapi/pureapi-core/database/types"
+)
+
+// defaultExplainDialect renders EXPLAIN statements as supported by
+// PostgreSQL and MySQL.
+type defaultExplainDialect struct {
+	analyze bool
+}
+
+// defaultExplainDialect implements the ExplainDialect interface.
+var _ types.ExplainDialect = (*defaultExplainDialect)(nil)
+
+// NewExplainDialect returns the standard EXPLAIN dialect.
+//
+// Returns:
+//   - *defaultExplainDialect: A new defaultExplainDialect instance.
+func NewExplainDialect() *defaultExplainDialect {
+	return &defaultExplainDialect{analyze: false}
+}
+
+// WithAnalyze returns a new dialect that renders EXPLAIN ANALYZE, which
+// executes the query to report actual timings. Use it with care for
+// statements that modify data.
+//
+// Returns:
+//   - *defaultExplainDialect: A new defaultExplainDialect instance.
+func (d *defaultExplainDialect) WithAnalyze() *defaultExplainDialect {
+	new := *d
+	new.analyze = true
+	return &new
+}
+
+// Explain returns the EXPLAIN statement for the query.
+//
+// Parameters:
+//   - query: The query to explain.
+//
+// Returns:
+//   - string: The EXPLAIN statement.
+func (d *defaultExplainDialect) Explain(query string) string {
+	if d.analyze {
+		return "EXPLAIN ANALYZE " + query
+	}
+	return "EXPLAIN " + query
+}
+
+// sqliteExplainDialect renders SQLite EXPLAIN QUERY PLAN statements.
+type sqliteExplainDialect struct{}
+
+// sqliteExplainDialect implements the ExplainDialect interface.
+var _ types.ExplainDialect = (*sqliteExplainDialect)(nil)
+
+// NewSQLiteExplainDialect returns the SQLite EXPLAIN QUERY PLAN dialect.
+// SQLite has no EXPLAIN ANALYZE.
+//
+// Returns:
+//   - *sqliteExplainDialect: A new sqliteExplainDialect instance.
+func NewSQLiteExplainDialect() *sqliteExplainDialect {
+	return &sqliteExplainDialect{}
+}
+
+// Explain returns the EXPLAIN QUERY PLAN statement for the query.
+//
+// Parameters:
+//   - query: The query to explain.
+//
+// Returns:
+//   - string: The EXPLAIN QUERY PLAN statement.
+func (d *sqliteExplainDialect) Explain(query string) string {
+	return "EXPLAIN QUERY PLAN " + query
+}
+
+// Explain returns the plan of a query using the standard EXPLAIN dialect.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - db: The database to explain the query on.
+//   - query: The query to explain.
+//   - parameters: The query parameters.
+//
+// Returns:
+//   - []map[string]any: The plan rows keyed by column name.
+//   - error: An error if the query can not be explained.
+func Explain(
+	ctx context.Context, db types.DB, query string, parameters []any,
+) ([]map[string]any, error) {
+	return ExplainWithDialect(ctx, db, NewExplainDialect(), query, parameters)
+}
+
+// ExplainWithDialect returns the plan of a query using the dialect to
+// render the explain statement. The plan rows are returned as maps keyed by
+// column name, since their columns differ between databases.
+//
+// Parameters:
+//   - ctx: Context to use.
+//   - db: The database to explain the query on.
+//   - dialect: The dialect rendering the explain statement.
+//   - query: The query to explain.
+//   - parameters: The query parameters.
+//
+// Returns:
+//   - []map[string]any: The plan rows keyed by column name.
+//   - error: An error if the query can not be explained.
+func ExplainWithDialect(
+	ctx context.Context,
+	db types.DB,
+	dialect types.ExplainDialect,
+	query string,
+	parameters []any,
+) ([]map[string]any, error) {
+	if dialect == nil {
+		return nil, fmt.Errorf("ExplainWithDialect: dialect is nil")
+	}
+	rows, err := QueryRaw(ctx, db, dialect.Explain(query), parameters, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ExplainWithDialect: %w", err)
+	}
+	defer rows.Close()
+	plan, err := RowsToMaps(ctx, rows)
+	if err != nil {
+		return nil, fmt.Errorf("ExplainWithDialect: %w", err)
+	}
+	return plan, nil
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// TestExplainWithDialect_SQLite verifies that a plan is returned for a
+// simple query on SQLite.
+func TestExplainWithDialect_SQLite(t *testing.T) {
+	db, err := Connect(
+		ConnectConfig{Driver: "sqlite3", MaxOpenConns: 1, MaxIdleConns: 1},
+		NewSQLDBAdapter,
+		":memory:",
+	)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)",
+	)
+	require.NoError(t, err)
+
+	plan, err := ExplainWithDialect(
+		context.Background(),
+		db,
+		NewSQLiteExplainDialect(),
+		"SELECT name FROM users WHERE id = ?",
+		[]any{1},
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, plan)
+	assert.Contains(t, plan[0], "detail")
+	assert.Contains(t, plan[0]["detail"], "users")
+}
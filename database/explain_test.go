@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// ExplainTestSuite is a test suite for the explain helpers.
+type ExplainTestSuite struct {
+	suite.Suite
+}
+
+// TestExplainTestSuite runs the test suite.
+func TestExplainTestSuite(t *testing.T) {
+	suite.Run(t, new(ExplainTestSuite))
+}
+
+// TestDialects tests the rendered explain statements.
+func (s *ExplainTestSuite) TestDialects() {
+	query := "SELECT * FROM t"
+	assert.Equal(
+		s.T(), "EXPLAIN SELECT * FROM t", NewExplainDialect().Explain(query),
+	)
+	assert.Equal(
+		s.T(), "EXPLAIN ANALYZE SELECT * FROM t",
+		NewExplainDialect().WithAnalyze().Explain(query),
+	)
+	assert.Equal(
+		s.T(), "EXPLAIN QUERY PLAN SELECT * FROM t",
+		NewSQLiteExplainDialect().Explain(query),
+	)
+}
+
+// TestExplainWithDialect_NilDialect tests that a nil dialect is rejected.
+func (s *ExplainTestSuite) TestExplainWithDialect_NilDialect() {
+	_, err := ExplainWithDialect(
+		context.Background(), &fakeDB{}, nil, "SELECT 1", nil,
+	)
+	require.Error(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "ExplainWithDialect: dialect is nil")
+}
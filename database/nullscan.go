@@ -0,0 +1,75 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// Nullable column types for ScanRow implementations. They are aliases of the
+// database/sql types.
+type (
+	NullString  = sql.NullString
+	NullInt64   = sql.NullInt64
+	NullInt32   = sql.NullInt32
+	NullFloat64 = sql.NullFloat64
+	NullBool    = sql.NullBool
+	NullTime    = sql.NullTime
+)
+
+// RowScanner is implemented by types.Row and types.Rows.
+type RowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scannerType is the reflect type of sql.Scanner.
+var scannerType = reflect.TypeFor[sql.Scanner]()
+
+// ScanInto scans the row into dest like Scan, but tolerates NULL columns.
+// When a column is NULL, a non-pointer target such as *string or *int64 is
+// set to its zero value instead of failing. Targets that are pointers to
+// pointers are set to nil, and targets implementing sql.Scanner, such as
+// NullString, handle NULL themselves.
+//
+// Parameters:
+//   - row: The row to scan.
+//   - dest: The scan targets. Each must be a non-nil pointer.
+//
+// Returns:
+//   - error: An error if the scan fails.
+func ScanInto(row RowScanner, dest ...any) error {
+	targets := make([]any, len(dest))
+	nullable := make([]reflect.Value, len(dest))
+	for i, d := range dest {
+		value := reflect.ValueOf(d)
+		if value.Kind() != reflect.Pointer || value.IsNil() {
+			return errors.New("ScanInto: destination must be a non-nil pointer")
+		}
+		elemType := value.Type().Elem()
+		if value.Type().Implements(scannerType) ||
+			elemType.Kind() == reflect.Pointer ||
+			elemType.Kind() == reflect.Interface {
+			targets[i] = d
+			continue
+		}
+		// Scan into a **T so that database/sql sets it to nil on NULL.
+		holder := reflect.New(reflect.PointerTo(elemType))
+		targets[i] = holder.Interface()
+		nullable[i] = holder
+	}
+	if err := row.Scan(targets...); err != nil {
+		return err
+	}
+	for i, holder := range nullable {
+		if !holder.IsValid() {
+			continue
+		}
+		target := reflect.ValueOf(dest[i]).Elem()
+		if holder.Elem().IsNil() {
+			target.SetZero()
+		} else {
+			target.Set(holder.Elem().Elem())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+// NullScanTestSuite is a suite of tests for ScanInto.
+type NullScanTestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+}
+
+// TestNullScanTestSuite runs the test suite.
+func TestNullScanTestSuite(t *testing.T) {
+	suite.Run(t, new(NullScanTestSuite))
+}
+
+// SetupTest creates the sqlmock database.
+func (s *NullScanTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.db = db
+	s.mock = mock
+}
+
+// TearDownTest closes the sqlmock database.
+func (s *NullScanTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+// queryRow returns the single row returned by the mock.
+func (s *NullScanTestSuite) queryRow(values ...driver.Value) *sql.Row {
+	rows := sqlmock.NewRows([]string{"name", "age", "nick", "created", "ns"})
+	rows.AddRow(values...)
+	s.mock.ExpectQuery("SELECT").WillReturnRows(rows)
+	return s.db.QueryRow("SELECT")
+}
+
+// Test_ScanInto_Null tests that NULL columns are scanned as zero values.
+func (s *NullScanTestSuite) Test_ScanInto_Null() {
+	row := s.queryRow(nil, nil, nil, nil, nil)
+
+	name, age := "old", int64(5)
+	nick := new(string)
+	var created time.Time
+	var ns NullString
+	err := ScanInto(row, &name, &age, &nick, &created, &ns)
+	s.Require().NoError(err)
+	s.Empty(name)
+	s.Zero(age)
+	s.Nil(nick)
+	s.True(created.IsZero())
+	s.False(ns.Valid)
+}
+
+// Test_ScanInto_Values tests that non-NULL columns are scanned normally.
+func (s *NullScanTestSuite) Test_ScanInto_Values() {
+	now := time.Now()
+	row := s.queryRow("alice", int64(30), "al", now, "set")
+
+	var name string
+	var age int64
+	var nick *string
+	var created time.Time
+	var ns NullString
+	err := ScanInto(row, &name, &age, &nick, &created, &ns)
+	s.Require().NoError(err)
+	s.Equal("alice", name)
+	s.Equal(int64(30), age)
+	s.Require().NotNil(nick)
+	s.Equal("al", *nick)
+	s.Equal(now, created)
+	s.Equal(NullString{String: "set", Valid: true}, ns)
+}
+
+// Test_ScanInto_Errors tests invalid destinations and scan errors.
+func (s *NullScanTestSuite) Test_ScanInto_Errors() {
+	var name string
+	s.Error(ScanInto(&fakeRow{}, name))
+	s.Error(ScanInto(&fakeRow{}, (*string)(nil)))
+
+	row := s.queryRow("alice", "not a number", nil, nil, nil)
+	var age int64
+	var nick *string
+	var created time.Time
+	var ns NullString
+	s.Error(ScanInto(row, &name, &age, &nick, &created, &ns))
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"github.com/pureapi/pureapi-core/database/types"
+)
+
+// RedactedParam replaces parameter values masked by the redacting masker.
+const RedactedParam = "[REDACTED]"
+
+// redactingParamMasker replaces every parameter value with RedactedParam.
+type redactingParamMasker struct{}
+
+// redactingParamMasker implements the ParamMasker interface.
+var _ types.ParamMasker = (*redactingParamMasker)(nil)
+
+// NewRedactingParamMasker creates a masker that redacts all parameter
+// values. It is the default masker.
+//
+// Returns:
+//   - *redactingParamMasker: A new redactingParamMasker instance.
+func NewRedactingParamMasker() *redactingParamMasker {
+	return &redactingParamMasker{}
+}
+
+// Mask returns a copy of the parameters with every value redacted.
+//
+// Parameters:
+//   - parameters: The query parameters.
+//
+// Returns:
+//   - []any: The masked parameters.
+func (m *redactingParamMasker) Mask(parameters []any) []any {
+	if parameters == nil {
+		return nil
+	}
+	masked := make([]any, len(parameters))
+	for i := range masked {
+		masked[i] = RedactedParam
+	}
+	return masked
+}
+
+// permissiveParamMasker keeps the parameter values as they are.
+type permissiveParamMasker struct{}
+
+// permissiveParamMasker implements the ParamMasker interface.
+var _ types.ParamMasker = (*permissiveParamMasker)(nil)
+
+// NewPermissiveParamMasker creates a masker that keeps all parameter
+// values. It is meant for local debugging only.
+//
+// Returns:
+//   - *permissiveParamMasker: A new permissiveParamMasker instance.
+func NewPermissiveParamMasker() *permissiveParamMasker {
+	return &permissiveParamMasker{}
+}
+
+// Mask returns the parameters unchanged.
+//
+// Parameters:
+//   - parameters: The query parameters.
+//
+// Returns:
+//   - []any: The parameters.
+func (m *permissiveParamMasker) Mask(parameters []any) []any {
+	return parameters
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// ParamMaskerTestSuite is a test suite for the param maskers.
+type ParamMaskerTestSuite struct {
+	suite.Suite
+}
+
+// TestParamMaskerTestSuite runs the test suite.
+func TestParamMaskerTestSuite(t *testing.T) {
+	suite.Run(t, new(ParamMaskerTestSuite))
+}
+
+// TestRedacting tests that the redacting masker redacts every value without
+// modifying the input.
+func (s *ParamMaskerTestSuite) TestRedacting() {
+	params := []any{"secret", 1, nil}
+	masked := NewRedactingParamMasker().Mask(params)
+	assert.Equal(
+		s.T(), []any{RedactedParam, RedactedParam, RedactedParam}, masked,
+	)
+	assert.Equal(s.T(), []any{"secret", 1, nil}, params)
+	assert.Nil(s.T(), NewRedactingParamMasker().Mask(nil))
+}
+
+// TestPermissive tests that the permissive masker keeps the values.
+func (s *ParamMaskerTestSuite) TestPermissive() {
+	params := []any{"secret", 1}
+	assert.Equal(s.T(), params, NewPermissiveParamMasker().Mask(params))
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pureapi/pureapi-core/database/types"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
+)
+
+// Define events.
+const (
+	// EventSlowQuery event is emitted when a statement takes longer than the
+	// slow query threshold.
+	EventSlowQuery utiltypes.EventType = "event_slow_query"
+	// EventQueryError event is emitted when a statement fails.
+	EventQueryError utiltypes.EventType = "event_query_error"
+)
+
+// minMaskedParamLength is the length below which parameter values are not
+// masked in error messages, as they can not be told apart from the rest of
+// the message.
+const minMaskedParamLength = 3
+
+// queryLoggerKey is the context key of the query logger.
+type queryLoggerKey struct{}
+
+// QueryLogger times the statements run by the dbops functions and emits an
+// EventSlowQuery event at the Warn level for statements slower than the
+// threshold, and an EventQueryError event at the Error level for failing
+// statements. Parameter values are masked with the param masker, which
+// redacts all values by default, in the events and in the errors returned by
+// the dbops functions.
+type QueryLogger struct {
+	emitterLogger utiltypes.EmitterLogger
+	threshold     time.Duration
+	omitParams    bool
+	paramMasker   types.ParamMasker
+	clock         func() time.Time
+}
+
+// NewQueryLogger creates a new QueryLogger.
+//
+// Parameters:
+//   - emitterLogger: The emitter logger to emit slow query events with.
+//   - threshold: The duration above which a statement is slow.
+//
+// Returns:
+//   - *QueryLogger: A new QueryLogger instance.
+func NewQueryLogger(
+	emitterLogger utiltypes.EmitterLogger, threshold time.Duration,
+) *QueryLogger {
+	return &QueryLogger{
+		emitterLogger: emitterLogger,
+		threshold:     threshold,
+		omitParams:    false,
+		paramMasker:   NewRedactingParamMasker(),
+		clock:         time.Now,
+	}
+}
+
+// WithOmitParams returns a new QueryLogger that leaves the parameter values
+// out of the events, for example because they may contain PII.
+//
+// Returns:
+//   - *QueryLogger: A new QueryLogger instance.
+func (l *QueryLogger) WithOmitParams() *QueryLogger {
+	new := *l
+	new.omitParams = true
+	return &new
+}
+
+// WithParamMasker returns a new QueryLogger that masks the parameter values
+// with masker.
+//
+// Parameters:
+//   - masker: The param masker.
+//
+// Returns:
+//   - *QueryLogger: A new QueryLogger instance.
+func (l *QueryLogger) WithParamMasker(masker types.ParamMasker) *QueryLogger {
+	new := *l
+	new.paramMasker = masker
+	return &new
+}
+
+// WithClock returns a new QueryLogger that reads the time from clock.
+//
+// Parameters:
+//   - clock: The function returning the current time.
+//
+// Returns:
+//   - *QueryLogger: A new QueryLogger instance.
+func (l *QueryLogger) WithClock(clock func() time.Time) *QueryLogger {
+	new := *l
+	new.clock = clock
+	return &new
+}
+
+// WithQueryLogger returns a new context carrying the query logger. The
+// dbops functions called with the context time their statements with it.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - logger: The query logger.
+//
+// Returns:
+//   - context.Context: The new context.
+func WithQueryLogger(ctx context.Context, logger *QueryLogger) context.Context {
+	return context.WithValue(ctx, queryLoggerKey{}, logger)
+}
+
+// observeQuery starts timing a statement. The returned function stops
+// timing and emits the slow query event if the context carries a query
+// logger and the statement was slow. If the statement failed, it masks the
+// parameter values in the error and emits the query error event. Errors are
+// masked with the query logger's param masker, or redact all values if the
+// context carries no query logger.
+func observeQuery(
+	ctx context.Context, query string, parameters []any,
+) func(errp *error) {
+	logger, ok := ctx.Value(queryLoggerKey{}).(*QueryLogger)
+	if !ok || logger == nil || logger.emitterLogger == nil {
+		return func(errp *error) {
+			if *errp != nil {
+				*errp = maskError(*errp, parameters, NewRedactingParamMasker())
+			}
+		}
+	}
+	start := logger.clock()
+	return func(errp *error) {
+		duration := logger.clock().Sub(start)
+		if *errp != nil {
+			*errp = maskError(*errp, parameters, logger.masker())
+			logger.emitError(query, parameters, duration, *errp)
+		}
+		if duration <= logger.threshold {
+			return
+		}
+		logger.emitterLogger.Warn(
+			utiltypes.NewEvent(
+				EventSlowQuery,
+				fmt.Sprintf("Slow query (%s): %s", duration, query),
+			).WithData(logger.eventData(query, parameters, duration)),
+		)
+	}
+}
+
+// emitError emits the query error event for err. Not found errors are not
+// emitted.
+func (l *QueryLogger) emitError(
+	query string, parameters []any, duration time.Duration, err error,
+) {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	data := l.eventData(query, parameters, duration)
+	data["error"] = err.Error()
+	l.emitterLogger.Error(
+		utiltypes.NewEvent(
+			EventQueryError,
+			fmt.Sprintf("Query error: %s: %s", query, err.Error()),
+		).WithData(data),
+	)
+}
+
+// eventData returns the data of the query events.
+func (l *QueryLogger) eventData(
+	query string, parameters []any, duration time.Duration,
+) map[string]any {
+	data := map[string]any{"query": query, "duration": duration}
+	if !l.omitParams {
+		data["parameters"] = l.masker().Mask(parameters)
+	}
+	return data
+}
+
+// masker returns the param masker, or the redacting masker if none is set.
+func (l *QueryLogger) masker() types.ParamMasker {
+	if l.paramMasker == nil {
+		return NewRedactingParamMasker()
+	}
+	return l.paramMasker
+}
+
+// maskedError is an error whose message has the parameter values masked.
+// Unwrap returns the original error, so errors.Is and errors.As still see
+// driver errors.
+type maskedError struct {
+	msg string
+	err error
+}
+
+// Error returns the masked message.
+//
+// Returns:
+//   - string: The masked message.
+func (e *maskedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error.
+//
+// Returns:
+//   - error: The original error.
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
+
+// maskError replaces the parameter values found in the error message with
+// their masked values. Nil and boolean values, and values shorter than
+// minMaskedParamLength, are left as they are.
+func maskError(err error, parameters []any, masker types.ParamMasker) error {
+	if len(parameters) == 0 {
+		return err
+	}
+	masked := masker.Mask(parameters)
+	msg := err.Error()
+	for i, parameter := range parameters {
+		raw := paramText(parameter)
+		if len(raw) < minMaskedParamLength {
+			continue
+		}
+		replacement := RedactedParam
+		if i < len(masked) {
+			replacement = fmt.Sprint(masked[i])
+		}
+		msg = strings.ReplaceAll(msg, raw, replacement)
+	}
+	if msg == err.Error() {
+		return err
+	}
+	return &maskedError{msg: msg, err: err}
+}
+
+// paramText returns the text of a parameter value as it would appear in an
+// error message, or an empty string for nil and boolean values.
+func paramText(parameter any) string {
+	switch value := parameter.(type) {
+	case nil, bool:
+		return ""
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pureapi/pureapi-core/database/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// stepClock returns a clock that advances by step on every call.
+func stepClock(step time.Duration) func() time.Time {
+	now := time.Unix(0, 0)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+// QueryLoggerTestSuite is a test suite for the query logger.
+type QueryLoggerTestSuite struct {
+	suite.Suite
+	emitterLogger *fakeEmitterLogger
+	db            *fakeDB
+}
+
+// TestQueryLoggerTestSuite runs the test suite.
+func TestQueryLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(QueryLoggerTestSuite))
+}
+
+// SetupTest sets up the test suite.
+func (s *QueryLoggerTestSuite) SetupTest() {
+	s.emitterLogger = &fakeEmitterLogger{}
+	s.db = &fakeDB{
+		execFunc: func(query string, args ...any) (types.Result, error) {
+			return &fakeResult{}, nil
+		},
+	}
+}
+
+// TestSlowQuery tests that a statement over the threshold emits a slow
+// query event with the query, duration and parameters.
+func (s *QueryLoggerTestSuite) TestSlowQuery() {
+	logger := NewQueryLogger(s.emitterLogger, time.Second).
+		WithClock(stepClock(2 * time.Second)).
+		WithParamMasker(NewPermissiveParamMasker())
+	ctx := WithQueryLogger(context.Background(), logger)
+	_, err := ExecRaw(ctx, s.db, "DELETE FROM t WHERE id = ?", []any{1}, nil)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), s.emitterLogger.events, 1)
+	event := s.emitterLogger.events[0]
+	assert.Equal(s.T(), EventSlowQuery, event.Type)
+	data, ok := event.Data.(map[string]any)
+	require.True(s.T(), ok)
+	assert.Equal(s.T(), "DELETE FROM t WHERE id = ?", data["query"])
+	assert.Equal(s.T(), 2*time.Second, data["duration"])
+	assert.Equal(s.T(), []any{1}, data["parameters"])
+}
+
+// TestFastQuery tests that a statement under the threshold emits nothing.
+func (s *QueryLoggerTestSuite) TestFastQuery() {
+	logger := NewQueryLogger(s.emitterLogger, time.Second).
+		WithClock(stepClock(time.Millisecond))
+	ctx := WithQueryLogger(context.Background(), logger)
+	_, err := ExecRaw(ctx, s.db, "SELECT 1", nil, nil)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), s.emitterLogger.events)
+}
+
+// TestMaskedParams tests that the parameters are redacted by default.
+func (s *QueryLoggerTestSuite) TestMaskedParams() {
+	logger := NewQueryLogger(s.emitterLogger, 0).
+		WithClock(stepClock(time.Second))
+	ctx := WithQueryLogger(context.Background(), logger)
+	_, err := ExecRaw(
+		ctx, s.db, "SELECT ?, ?", []any{"alice@example.com", 42}, nil,
+	)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), s.emitterLogger.events, 1)
+	event := s.emitterLogger.events[0]
+	data, ok := event.Data.(map[string]any)
+	require.True(s.T(), ok)
+	assert.Equal(
+		s.T(), []any{RedactedParam, RedactedParam}, data["parameters"],
+	)
+	assert.NotContains(s.T(), event.Message, "alice@example.com")
+}
+
+// TestOmitParams tests that the parameters can be left out of the event.
+func (s *QueryLoggerTestSuite) TestOmitParams() {
+	logger := NewQueryLogger(s.emitterLogger, 0).
+		WithClock(stepClock(time.Second)).
+		WithOmitParams()
+	ctx := WithQueryLogger(context.Background(), logger)
+	_, err := ExecRaw(ctx, s.db, "SELECT ?", []any{"secret"}, nil)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), s.emitterLogger.events, 1)
+	data, ok := s.emitterLogger.events[0].Data.(map[string]any)
+	require.True(s.T(), ok)
+	assert.NotContains(s.T(), data, "parameters")
+}
+
+// TestNoLogger tests that contexts without a query logger are not timed.
+func (s *QueryLoggerTestSuite) TestNoLogger() {
+	_, err := ExecRaw(context.Background(), s.db, "SELECT 1", nil, nil)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), s.emitterLogger.events)
+}
+
+// driverError is a driver error that embeds a parameter value in its
+// message, like a unique constraint violation.
+type driverError struct {
+	value string
+}
+
+func (e *driverError) Error() string {
+	return fmt.Sprintf("duplicate key value (email)=(%s)", e.value)
+}
+
+// TestQueryErrorMasked tests that a failing query's error and error event
+// contain no raw parameter value, while the driver error stays reachable.
+func (s *QueryLoggerTestSuite) TestQueryErrorMasked() {
+	const email = "alice@example.com"
+	s.db.execFunc = func(query string, args ...any) (types.Result, error) {
+		return nil, &driverError{value: email}
+	}
+	logger := NewQueryLogger(s.emitterLogger, time.Hour).
+		WithClock(stepClock(time.Millisecond))
+	ctx := WithQueryLogger(context.Background(), logger)
+	query := "INSERT INTO users (email) VALUES (?)"
+	_, err := ExecRaw(
+		ctx, s.db, query, []any{email}, &fakeErrorChecker{prefix: "db: "},
+	)
+	require.Error(s.T(), err)
+	assert.NotContains(s.T(), err.Error(), email)
+	assert.Equal(
+		s.T(),
+		"db: duplicate key value (email)=("+RedactedParam+")",
+		err.Error(),
+	)
+
+	require.Len(s.T(), s.emitterLogger.events, 1)
+	event := s.emitterLogger.events[0]
+	assert.Equal(s.T(), EventQueryError, event.Type)
+	assert.NotContains(s.T(), event.Message, email)
+	data, ok := event.Data.(map[string]any)
+	require.True(s.T(), ok)
+	assert.NotContains(s.T(), fmt.Sprint(data), email)
+	assert.Equal(s.T(), []any{RedactedParam}, data["parameters"])
+
+	// Without a query logger the error is still masked, and errors.As
+	// reaches the driver error.
+	_, err = ExecRaw(context.Background(), s.db, query, []any{email}, nil)
+	require.Error(s.T(), err)
+	assert.NotContains(s.T(), err.Error(), email)
+	var driverErr *driverError
+	assert.True(s.T(), errors.As(err, &driverErr))
+}
+
+// TestQueryErrorPermissive tests that the permissive masker keeps parameter
+// values in errors.
+func (s *QueryLoggerTestSuite) TestQueryErrorPermissive() {
+	s.db.execFunc = func(query string, args ...any) (types.Result, error) {
+		return nil, &driverError{value: "bob@example.com"}
+	}
+	logger := NewQueryLogger(s.emitterLogger, time.Hour).
+		WithParamMasker(NewPermissiveParamMasker())
+	ctx := WithQueryLogger(context.Background(), logger)
+	_, err := ExecRaw(ctx, s.db, "INSERT", []any{"bob@example.com"}, nil)
+	require.Error(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "bob@example.com")
+}
@@ -0,0 +1,458 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pureapi/pureapi-core/database/types"
+)
+
+// DefaultReplicaHealthTTL is how long the result of a replica health check
+// is reused before the replica is pinged again.
+const DefaultReplicaHealthTTL = 5 * time.Second
+
+// lockingReadRegexp matches SELECT statements that take locks or write
+// rows, which must run on the primary.
+var lockingReadRegexp = regexp.MustCompile(
+	`(?i)\bFOR\s+(NO\s+KEY\s+)?(UPDATE|SHARE|KEY\s+SHARE)\b|` +
+		`\bINTO\b|\bRETURNING\b`,
+)
+
+// ReplicaDB routes reads to replica connections and writes to a primary
+// connection. Plain SELECT statements run with Query, QueryRow or Prepare go
+// to a replica chosen by the replica selector. Exec, BeginTx and all other
+// statements, including locking reads such as SELECT ... FOR UPDATE and
+// statements with RETURNING or INTO, go to the primary. Replica
+// health is checked with a ping whose result is cached for the health TTL,
+// and if no replica is healthy the read falls back to the primary.
+type ReplicaDB struct {
+	primary   types.DB
+	replicas  []types.DB
+	selector  types.ReplicaSelector
+	healthTTL time.Duration
+	health    *replicaHealth
+}
+
+// replicaHealth caches the results of replica health checks.
+type replicaHealth struct {
+	mu      sync.Mutex
+	entries map[types.DB]replicaHealthEntry
+	now     func() time.Time
+}
+
+// replicaHealthEntry is the cached result of a replica health check.
+type replicaHealthEntry struct {
+	healthy   bool
+	checkedAt time.Time
+}
+
+// ReplicaDB implements the DB interface.
+var _ types.DB = (*ReplicaDB)(nil)
+
+// NewReplicaDB creates a new ReplicaDB with a round-robin replica selector.
+//
+// Parameters:
+//   - primary: The primary connection used for writes.
+//   - replicas: The replica connections used for reads.
+//
+// Returns:
+//   - *ReplicaDB: A new ReplicaDB instance.
+func NewReplicaDB(primary types.DB, replicas ...types.DB) *ReplicaDB {
+	return &ReplicaDB{
+		primary:   primary,
+		replicas:  replicas,
+		selector:  NewRoundRobinSelector(),
+		healthTTL: DefaultReplicaHealthTTL,
+		health: &replicaHealth{
+			entries: map[types.DB]replicaHealthEntry{},
+			now:     time.Now,
+		},
+	}
+}
+
+// WithSelector sets the replica selector. It returns a new ReplicaDB.
+//
+// Parameters:
+//   - selector: The replica selector.
+//
+// Returns:
+//   - *ReplicaDB: A new ReplicaDB instance.
+func (db *ReplicaDB) WithSelector(selector types.ReplicaSelector) *ReplicaDB {
+	new := *db
+	new.selector = selector
+	return &new
+}
+
+// WithHealthTTL sets how long replica health check results are reused. A
+// non-positive TTL pings the replica before every read. It returns a new
+// ReplicaDB.
+//
+// Parameters:
+//   - ttl: The health check TTL.
+//
+// Returns:
+//   - *ReplicaDB: A new ReplicaDB instance.
+func (db *ReplicaDB) WithHealthTTL(ttl time.Duration) *ReplicaDB {
+	new := *db
+	new.healthTTL = ttl
+	return &new
+}
+
+// Primary returns the primary connection.
+//
+// Returns:
+//   - DB: The primary connection.
+func (db *ReplicaDB) Primary() types.DB {
+	return db.primary
+}
+
+// Replicas returns the replica connections.
+//
+// Returns:
+//   - []DB: The replica connections.
+func (db *ReplicaDB) Replicas() []types.DB {
+	return db.replicas
+}
+
+// Ping pings the primary connection.
+//
+// Returns:
+//   - error: An error if the ping fails.
+func (db *ReplicaDB) Ping() error {
+	return db.primary.Ping()
+}
+
+// Stats returns the connection pool statistics summed over the primary and
+// all replicas. Connections without statistics are skipped.
+//
+// Returns:
+//   - sql.DBStats: The summed connection pool statistics.
+func (db *ReplicaDB) Stats() sql.DBStats {
+	var total sql.DBStats
+	for _, conn := range db.all() {
+		stats, _ := Stats(conn)
+		total.MaxOpenConnections += stats.MaxOpenConnections
+		total.OpenConnections += stats.OpenConnections
+		total.InUse += stats.InUse
+		total.Idle += stats.Idle
+		total.WaitCount += stats.WaitCount
+		total.WaitDuration += stats.WaitDuration
+		total.MaxIdleClosed += stats.MaxIdleClosed
+		total.MaxIdleTimeClosed += stats.MaxIdleTimeClosed
+		total.MaxLifetimeClosed += stats.MaxLifetimeClosed
+	}
+	return total
+}
+
+// PingContext pings the primary connection.
+//
+// Parameters:
+//   - ctx: The context for the ping.
+//
+// Returns:
+//   - error: An error if the ping fails.
+func (db *ReplicaDB) PingContext(ctx context.Context) error {
+	return db.primary.PingContext(ctx)
+}
+
+// SetConnMaxLifetime sets the maximum connection lifetime on all connections.
+//
+// Parameters:
+//   - d: The maximum lifetime of a connection.
+func (db *ReplicaDB) SetConnMaxLifetime(d time.Duration) {
+	for _, conn := range db.all() {
+		conn.SetConnMaxLifetime(d)
+	}
+}
+
+// SetConnMaxIdleTime sets the maximum connection idle time on all connections.
+//
+// Parameters:
+//   - d: The maximum idle time of a connection.
+func (db *ReplicaDB) SetConnMaxIdleTime(d time.Duration) {
+	for _, conn := range db.all() {
+		conn.SetConnMaxIdleTime(d)
+	}
+}
+
+// SetMaxOpenConns sets the maximum number of open connections on all
+// connections.
+//
+// Parameters:
+//   - n: The maximum number of open connections.
+func (db *ReplicaDB) SetMaxOpenConns(n int) {
+	for _, conn := range db.all() {
+		conn.SetMaxOpenConns(n)
+	}
+}
+
+// SetMaxIdleConns sets the maximum number of idle connections on all
+// connections.
+//
+// Parameters:
+//   - n: The maximum number of idle connections.
+func (db *ReplicaDB) SetMaxIdleConns(n int) {
+	for _, conn := range db.all() {
+		conn.SetMaxIdleConns(n)
+	}
+}
+
+// Prepare prepares a statement. Plain SELECT statements are prepared on a
+// replica and all other statements on the primary.
+//
+// Parameters:
+//   - query: The SQL query string to prepare.
+//
+// Returns:
+//   - Stmt: The prepared statement.
+//   - error: An error if the statement cannot be prepared.
+func (db *ReplicaDB) Prepare(query string) (types.Stmt, error) {
+	return db.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext prepares a statement using the provided context. Plain
+// SELECT statements are prepared on a replica and all other statements,
+// including SELECT ... FOR UPDATE and SELECT ... INTO, on the primary.
+//
+// Parameters:
+//   - ctx: The context for the preparation.
+//   - query: The SQL query string to prepare.
+//
+// Returns:
+//   - Stmt: The prepared statement.
+//   - error: An error if the statement cannot be prepared.
+func (db *ReplicaDB) PrepareContext(
+	ctx context.Context, query string,
+) (types.Stmt, error) {
+	return db.target(ctx, query).PrepareContext(ctx, query)
+}
+
+// BeginTx begins a transaction on the primary connection.
+//
+// Parameters:
+//   - ctx: The context for the transaction.
+//   - opts: The transaction options.
+//
+// Returns:
+//   - Tx: The transaction.
+//   - error: An error if the transaction cannot be created.
+func (db *ReplicaDB) BeginTx(
+	ctx context.Context, opts *sql.TxOptions,
+) (types.Tx, error) {
+	return db.primary.BeginTx(ctx, opts)
+}
+
+// Exec executes a query on the primary connection.
+//
+// Parameters:
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Result: The result of the query.
+//   - error: An error if the query fails.
+func (db *ReplicaDB) Exec(query string, args ...any) (types.Result, error) {
+	return db.primary.Exec(query, args...)
+}
+
+// ExecContext executes a query on the primary connection using the provided
+// context.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Result: The result of the query.
+//   - error: An error if the query fails.
+func (db *ReplicaDB) ExecContext(
+	ctx context.Context, query string, args ...any,
+) (types.Result, error) {
+	return db.primary.ExecContext(ctx, query, args...)
+}
+
+// Query executes a query that returns rows. Plain reads run on a replica
+// connection, other statements on the primary.
+//
+// Parameters:
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Rows: The rows of the query.
+//   - error: An error if the query fails.
+func (db *ReplicaDB) Query(query string, args ...any) (types.Rows, error) {
+	return db.target(context.Background(), query).Query(query, args...)
+}
+
+// QueryContext executes a query that returns rows using the provided
+// context. Plain reads run on a replica connection, other statements on the
+// primary.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Rows: The rows of the query.
+//   - error: An error if the query fails.
+func (db *ReplicaDB) QueryContext(
+	ctx context.Context, query string, args ...any,
+) (types.Rows, error) {
+	return db.target(ctx, query).QueryContext(ctx, query, args...)
+}
+
+// QueryRow executes a query that returns a single row. Plain reads run on a
+// replica connection, other statements on the primary.
+//
+// Parameters:
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Row: The row of the query.
+func (db *ReplicaDB) QueryRow(query string, args ...any) types.Row {
+	return db.target(context.Background(), query).QueryRow(query, args...)
+}
+
+// QueryRowContext executes a query that returns a single row using the
+// provided context. Plain reads run on a replica connection, other
+// statements on the primary.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Row: The row of the query.
+func (db *ReplicaDB) QueryRowContext(
+	ctx context.Context, query string, args ...any,
+) types.Row {
+	return db.target(ctx, query).QueryRowContext(ctx, query, args...)
+}
+
+// Close closes the primary and all replica connections.
+//
+// Returns:
+//   - error: An error joining all errors encountered while closing.
+func (db *ReplicaDB) Close() error {
+	var errs []error
+	for _, conn := range db.all() {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("ReplicaDB.Close error: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// target returns the connection the query runs on: a replica for plain reads
+// and the primary for everything else.
+func (db *ReplicaDB) target(ctx context.Context, query string) types.DB {
+	if isReadQuery(query) {
+		return db.reader(ctx)
+	}
+	return db.primary
+}
+
+// reader returns a healthy replica, or the primary if none is available.
+func (db *ReplicaDB) reader(ctx context.Context) types.DB {
+	candidates := slices.Clone(db.replicas)
+	for len(candidates) > 0 {
+		replica := db.selector.Select(candidates)
+		if replica == nil {
+			break
+		}
+		if db.healthy(ctx, replica) {
+			return replica
+		}
+		candidates = slices.DeleteFunc(candidates, func(c types.DB) bool {
+			return c == replica
+		})
+	}
+	return db.primary
+}
+
+// healthy reports whether the replica is healthy. A cached result younger
+// than the health TTL is reused, otherwise the replica is pinged with ctx.
+// Pings cancelled by ctx are not cached, since they say nothing about the
+// replica.
+func (db *ReplicaDB) healthy(ctx context.Context, replica types.DB) bool {
+	h := db.health
+	h.mu.Lock()
+	entry, ok := h.entries[replica]
+	h.mu.Unlock()
+	if ok && db.healthTTL > 0 && h.now().Sub(entry.checkedAt) < db.healthTTL {
+		return entry.healthy
+	}
+	err := replica.PingContext(ctx)
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+	h.mu.Lock()
+	h.entries[replica] = replicaHealthEntry{
+		healthy: err == nil, checkedAt: h.now(),
+	}
+	h.mu.Unlock()
+	return err == nil
+}
+
+// isReadQuery reports whether the query is a plain SELECT statement that
+// can run on a replica.
+func isReadQuery(query string) bool {
+	fields := strings.Fields(strings.TrimLeft(query, " \t\r\n("))
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "SELECT") {
+		return false
+	}
+	return !lockingReadRegexp.MatchString(query)
+}
+
+// all returns the primary and all replica connections.
+func (db *ReplicaDB) all() []types.DB {
+	return append([]types.DB{db.primary}, db.replicas...)
+}
+
+// roundRobinSelector selects replicas in turn.
+type roundRobinSelector struct {
+	next atomic.Uint64
+}
+
+// roundRobinSelector implements the ReplicaSelector interface.
+var _ types.ReplicaSelector = (*roundRobinSelector)(nil)
+
+// NewRoundRobinSelector creates a replica selector that cycles through the
+// replicas. It is safe for concurrent use.
+//
+// Returns:
+//   - *roundRobinSelector: A new roundRobinSelector instance.
+func NewRoundRobinSelector() *roundRobinSelector {
+	return &roundRobinSelector{}
+}
+
+// Select returns the next replica in turn.
+//
+// Parameters:
+//   - replicas: The replicas to select from.
+//
+// Returns:
+//   - DB: The selected replica, or nil if there are no replicas.
+func (s *roundRobinSelector) Select(replicas []types.DB) types.DB {
+	if len(replicas) == 0 {
+		return nil
+	}
+	n := s.next.Add(1) - 1
+	return replicas[n%uint64(len(replicas))]
+}
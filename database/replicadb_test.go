@@ -0,0 +1,319 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pureapi/pureapi-core/database/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// routedDB is a fake DB that records which connection served a call.
+type routedDB struct {
+	fakeDB
+	name    string
+	pingErr error
+	pings   int
+	served  *[]string
+}
+
+func newRoutedDB(name string, served *[]string) *routedDB {
+	db := &routedDB{name: name, served: served}
+	db.execFunc = func(query string, args ...any) (types.Result, error) {
+		*db.served = append(*db.served, db.name)
+		return &fakeResult{}, nil
+	}
+	db.queryFunc = func(query string, args ...any) (types.Rows, error) {
+		*db.served = append(*db.served, db.name)
+		return &fakeRows{}, nil
+	}
+	return db
+}
+
+func (db *routedDB) Ping() error {
+	return db.PingContext(context.Background())
+}
+
+func (db *routedDB) PingContext(ctx context.Context) error {
+	db.pings++
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return db.pingErr
+}
+
+func (db *routedDB) Prepare(query string) (types.Stmt, error) {
+	return db.PrepareContext(context.Background(), query)
+}
+
+func (db *routedDB) PrepareContext(
+	_ context.Context, query string,
+) (types.Stmt, error) {
+	*db.served = append(*db.served, db.name)
+	return &fakeStmt{
+		queryFunc: func(args ...any) (types.Rows, error) {
+			return &fakeRows{}, nil
+		},
+		queryRowFunc: func(args ...any) types.Row {
+			return &fakeRow{}
+		},
+		execFunc: func(args ...any) (types.Result, error) {
+			return &fakeResult{}, nil
+		},
+		closeFunc: func() error { return nil },
+	}, nil
+}
+
+// ReplicaDBTestSuite is a test suite for ReplicaDB.
+type ReplicaDBTestSuite struct {
+	suite.Suite
+	served   []string
+	primary  *routedDB
+	replica1 *routedDB
+	replica2 *routedDB
+}
+
+// TestReplicaDBTestSuite runs the test suite.
+func TestReplicaDBTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplicaDBTestSuite))
+}
+
+// SetupTest creates a primary and two replicas.
+func (s *ReplicaDBTestSuite) SetupTest() {
+	s.served = nil
+	s.primary = newRoutedDB("primary", &s.served)
+	s.replica1 = newRoutedDB("replica1", &s.served)
+	s.replica2 = newRoutedDB("replica2", &s.served)
+}
+
+// Test_ReadsRoundRobin tests that reads are spread over the replicas in turn.
+func (s *ReplicaDBTestSuite) Test_ReadsRoundRobin() {
+	db := NewReplicaDB(s.primary, s.replica1, s.replica2)
+	for range 4 {
+		_, err := db.Query("SELECT 1")
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(
+		s.T(),
+		[]string{"replica1", "replica2", "replica1", "replica2"},
+		s.served,
+	)
+}
+
+// Test_Stats tests that the pool statistics are summed over all connections.
+func (s *ReplicaDBTestSuite) Test_Stats() {
+	s.primary.stats = sql.DBStats{InUse: 1, WaitCount: 2}
+	s.replica1.stats = sql.DBStats{InUse: 3, WaitDuration: time.Second}
+	db := NewReplicaDB(s.primary, s.replica1)
+
+	stats := db.Stats()
+	assert.Equal(s.T(), 4, stats.InUse)
+	assert.Equal(s.T(), int64(2), stats.WaitCount)
+	assert.Equal(s.T(), time.Second, stats.WaitDuration)
+}
+
+// Test_WritesGoToPrimary tests that Exec and the raw helpers route writes to
+// the primary.
+func (s *ReplicaDBTestSuite) Test_WritesGoToPrimary() {
+	db := NewReplicaDB(s.primary, s.replica1)
+	_, err := db.Exec("UPDATE t SET a = 1")
+	require.NoError(s.T(), err)
+	_, err = ExecRaw(context.Background(), db, "UPDATE t SET a = 1", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = QueryRaw(context.Background(), db, "SELECT a FROM t", nil, nil)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"primary", "primary", "replica1"}, s.served)
+}
+
+// Test_SkipsUnhealthyReplica tests that a replica failing its ping is skipped.
+func (s *ReplicaDBTestSuite) Test_SkipsUnhealthyReplica() {
+	s.replica1.pingErr = errors.New("down")
+	db := NewReplicaDB(s.primary, s.replica1, s.replica2)
+	for range 2 {
+		_, err := db.Query("SELECT 1")
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), []string{"replica2", "replica2"}, s.served)
+}
+
+// Test_FallbackToPrimary tests that reads go to the primary when all replicas
+// are unhealthy or there are none.
+func (s *ReplicaDBTestSuite) Test_FallbackToPrimary() {
+	s.replica1.pingErr = errors.New("down")
+	s.replica2.pingErr = errors.New("down")
+	_, err := NewReplicaDB(s.primary, s.replica1, s.replica2).Query("SELECT 1")
+	require.NoError(s.T(), err)
+	_, err = NewReplicaDB(s.primary).Query("SELECT 1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"primary", "primary"}, s.served)
+}
+
+// Test_PreparedReads tests that the dbops functions, which prepare their
+// statements, send plain SELECT statements to a replica and everything else
+// to the primary.
+func (s *ReplicaDBTestSuite) Test_PreparedReads() {
+	ctx := context.Background()
+	db := NewReplicaDB(s.primary, s.replica1)
+	rows, stmt, err := Query(ctx, db, "SELECT a FROM t", nil, nil)
+	require.NoError(s.T(), err)
+	rows.Close()
+	stmt.Close()
+	_, err = QuerySingleValue(
+		ctx, db, " select count(*) from t", nil, nil,
+		func() *int { return new(int) },
+	)
+	require.NoError(s.T(), err)
+	_, _, err = Query(ctx, db, "SELECT a FROM t FOR UPDATE", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = Exec(ctx, db, "UPDATE t SET a = 1", nil, nil)
+	require.NoError(s.T(), err)
+	assert.Equal(
+		s.T(),
+		[]string{"replica1", "replica1", "primary", "primary"},
+		s.served,
+	)
+}
+
+// Test_IsReadQuery tests the classification of replica-safe statements.
+func (s *ReplicaDBTestSuite) Test_IsReadQuery() {
+	assert.True(s.T(), isReadQuery("SELECT 1"))
+	assert.True(s.T(), isReadQuery("\n\t(select id from t)"))
+	assert.False(s.T(), isReadQuery("SELECT * FROM t FOR UPDATE"))
+	assert.False(s.T(), isReadQuery("select * from t for no key update"))
+	assert.False(s.T(), isReadQuery("SELECT * FROM t FOR SHARE"))
+	assert.False(s.T(), isReadQuery("SELECT * INTO t2 FROM t"))
+	assert.False(s.T(), isReadQuery("SELECT f() RETURNING id"))
+	assert.False(s.T(), isReadQuery("INSERT INTO t VALUES (1)"))
+	assert.False(s.T(), isReadQuery("WITH x AS (DELETE FROM t) SELECT 1"))
+	assert.False(s.T(), isReadQuery(""))
+}
+
+// Test_QueriesRouteByStatement tests with sqlmock that Query, QueryContext,
+// QueryRow and QueryRowContext send locking reads and RETURNING statements
+// to the primary and plain reads to the replica.
+func (s *ReplicaDBTestSuite) Test_QueriesRouteByStatement() {
+	primaryConn, primaryMock, err := sqlmock.New()
+	require.NoError(s.T(), err)
+	defer primaryConn.Close()
+	replicaConn, replicaMock, err := sqlmock.New()
+	require.NoError(s.T(), err)
+	defer replicaConn.Close()
+	db := NewReplicaDB(&sqlDB{DB: primaryConn}, &sqlDB{DB: replicaConn})
+	ctx := context.Background()
+
+	locking := "SELECT id FROM t WHERE id = ? FOR UPDATE"
+	returning := "INSERT INTO t (a) VALUES (?) RETURNING id"
+	plain := "SELECT id FROM t"
+	rows := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"id"}) }
+	for range 2 {
+		primaryMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(rows())
+		primaryMock.ExpectQuery(`RETURNING id`).WillReturnRows(rows())
+		replicaMock.ExpectQuery(`SELECT id FROM t`).WillReturnRows(rows())
+	}
+
+	for _, query := range []string{locking, returning, plain} {
+		r, err := db.Query(query, 1)
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), r.Close())
+	}
+	for _, query := range []string{locking, returning, plain} {
+		r, err := db.QueryContext(ctx, query, 1)
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), r.Close())
+	}
+	require.NoError(s.T(), primaryMock.ExpectationsWereMet())
+	require.NoError(s.T(), replicaMock.ExpectationsWereMet())
+
+	primaryMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(rows().AddRow(1))
+	primaryMock.ExpectQuery(`RETURNING id`).WillReturnRows(rows().AddRow(2))
+	replicaMock.ExpectQuery(`SELECT id FROM t`).WillReturnRows(rows().AddRow(3))
+	var id int
+	require.NoError(s.T(), db.QueryRow(locking, 1).Scan(&id))
+	assert.Equal(s.T(), 1, id)
+	require.NoError(s.T(), db.QueryRowContext(ctx, returning, 1).Scan(&id))
+	assert.Equal(s.T(), 2, id)
+	require.NoError(s.T(), db.QueryRowContext(ctx, plain).Scan(&id))
+	assert.Equal(s.T(), 3, id)
+	require.NoError(s.T(), primaryMock.ExpectationsWereMet())
+	require.NoError(s.T(), replicaMock.ExpectationsWereMet())
+}
+
+// Test_HealthCached tests that replica health is cached for the health TTL
+// instead of pinging on every read.
+func (s *ReplicaDBTestSuite) Test_HealthCached() {
+	now := time.Unix(0, 0)
+	s.replica1.pingErr = errors.New("down")
+	db := NewReplicaDB(s.primary, s.replica1).WithHealthTTL(time.Second)
+	db.health.now = func() time.Time { return now }
+	for range 3 {
+		_, err := db.Query("SELECT 1")
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), 1, s.replica1.pings)
+	assert.Equal(s.T(), []string{"primary", "primary", "primary"}, s.served)
+
+	// Once the TTL has passed the replica is pinged again and used.
+	s.replica1.pingErr = nil
+	now = now.Add(2 * time.Second)
+	_, err := db.Query("SELECT 1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, s.replica1.pings)
+	assert.Equal(s.T(), "replica1", s.served[len(s.served)-1])
+}
+
+// Test_HealthUsesContext tests that the health check uses the read's
+// context and that cancelled checks are not cached.
+func (s *ReplicaDBTestSuite) Test_HealthUsesContext() {
+	db := NewReplicaDB(s.primary, s.replica1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := db.QueryContext(ctx, "SELECT 1")
+	require.NoError(s.T(), err)
+	_, err = db.QueryContext(context.Background(), "SELECT 1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"primary", "replica1"}, s.served)
+	assert.Equal(s.T(), 2, s.replica1.pings)
+}
+
+// fixedSelector always selects the last replica.
+type fixedSelector struct{}
+
+func (fixedSelector) Select(replicas []types.DB) types.DB {
+	return replicas[len(replicas)-1]
+}
+
+// Test_WithSelector tests that a custom selector is used.
+func (s *ReplicaDBTestSuite) Test_WithSelector() {
+	db := NewReplicaDB(s.primary, s.replica1, s.replica2).
+		WithSelector(fixedSelector{})
+	for range 2 {
+		_, err := db.Query("SELECT 1")
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), []string{"replica2", "replica2"}, s.served)
+}
+
+// Test_Close tests that Close closes all connections and joins errors.
+func (s *ReplicaDBTestSuite) Test_Close() {
+	closed := 0
+	closeFn := func() error {
+		closed++
+		return nil
+	}
+	s.primary.closeFunc = closeFn
+	s.replica1.closeFunc = func() error {
+		closed++
+		return errors.New("close failed")
+	}
+	s.replica2.closeFunc = closeFn
+	err := NewReplicaDB(s.primary, s.replica1, s.replica2).Close()
+	require.Error(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "close failed")
+	assert.Equal(s.T(), 3, closed)
+}
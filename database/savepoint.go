@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/pureapi/pureapi-core/database/types"
+)
+
+// savepointNameRegexp matches the allowed savepoint names.
+var savepointNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// defaultSavepointDialect renders standard SQL savepoint statements, which are
+// supported by PostgreSQL, MySQL and SQLite.
+type defaultSavepointDialect struct{}
+
+// defaultSavepointDialect implements the SavepointDialect interface.
+var _ types.SavepointDialect = (*defaultSavepointDialect)(nil)
+
+// NewSavepointDialect returns the standard SQL savepoint dialect.
+//
+// Returns:
+//   - *defaultSavepointDialect: A new defaultSavepointDialect instance.
+func NewSavepointDialect() *defaultSavepointDialect {
+	return &defaultSavepointDialect{}
+}
+
+// Savepoint returns the statement that creates the savepoint.
+//
+// Parameters:
+//   - name: The name of the savepoint.
+//
+// Returns:
+//   - string: The SAVEPOINT statement.
+func (d *defaultSavepointDialect) Savepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// Release returns the statement that releases the savepoint.
+//
+// Parameters:
+//   - name: The name of the savepoint.
+//
+// Returns:
+//   - string: The RELEASE SAVEPOINT statement.
+func (d *defaultSavepointDialect) Release(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+// RollbackTo returns the statement that rolls back to the savepoint.
+//
+// Parameters:
+//   - name: The name of the savepoint.
+//
+// Returns:
+//   - string: The ROLLBACK TO SAVEPOINT statement.
+func (d *defaultSavepointDialect) RollbackTo(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// Savepoint executes a TxFn within a savepoint of an existing transaction,
+// using standard SQL savepoint statements. It releases the savepoint if the
+// TxFn succeeds and rolls back to it if the TxFn returns an error or panics.
+// Panics are propagated after the rollback.
+//
+// Parameters:
+//   - ctx: The context for the savepoint.
+//   - tx: The transaction to create the savepoint in.
+//   - name: The name of the savepoint. Must be a valid identifier.
+//   - txFn: The function to execute in the savepoint.
+//
+// Returns:
+//   - Result: The result of the transactional function.
+//   - error: An error if the savepoint or the function fails.
+func Savepoint[Result any](
+	ctx context.Context, tx types.Tx, name string, txFn types.TxFn[Result],
+) (Result, error) {
+	return SavepointWithDialect(ctx, tx, NewSavepointDialect(), name, txFn)
+}
+
+// SavepointWithDialect is like Savepoint but renders the savepoint statements
+// with the given dialect.
+//
+// Parameters:
+//   - ctx: The context for the savepoint.
+//   - tx: The transaction to create the savepoint in.
+//   - dialect: The dialect used to render the savepoint statements.
+//   - name: The name of the savepoint. Must be a valid identifier.
+//   - txFn: The function to execute in the savepoint.
+//
+// Returns:
+//   - Result: The result of the transactional function.
+//   - error: An error if the savepoint or the function fails.
+func SavepointWithDialect[Result any](
+	ctx context.Context,
+	tx types.Tx,
+	dialect types.SavepointDialect,
+	name string,
+	txFn types.TxFn[Result],
+) (result Result, spErr error) {
+	if !savepointNameRegexp.MatchString(name) {
+		return result, fmt.Errorf("Savepoint: invalid savepoint name %q", name)
+	}
+	if _, err := tx.ExecContext(ctx, dialect.Savepoint(name)); err != nil {
+		return result, fmt.Errorf("Savepoint: create error: %w", err)
+	}
+	defer func() {
+		// Recover from panics.
+		var recovered any
+		panicOccurred := false
+		if recovered = recover(); recovered != nil {
+			panicOccurred = true
+			spErr = fmt.Errorf("Savepoint TxFn panicked: %v", recovered)
+		}
+		// Release or roll back to the savepoint.
+		if err := finalizeSavepoint(ctx, tx, dialect, name, spErr); err != nil {
+			spErr = err
+			var zero Result
+			result = zero
+		}
+		// Propagate the panic if there was one.
+		if panicOccurred {
+			panic(recovered)
+		}
+	}()
+	return txFn(ctx, tx)
+}
+
+// finalizeSavepoint releases or rolls back to a savepoint.
+func finalizeSavepoint(
+	ctx context.Context,
+	tx types.Tx,
+	dialect types.SavepointDialect,
+	name string,
+	spErr error,
+) error {
+	if spErr != nil {
+		if _, err := tx.ExecContext(ctx, dialect.RollbackTo(name)); err != nil {
+			return fmt.Errorf(
+				"finalizeSavepoint rollback error: %w; original error: %w",
+				err, spErr,
+			)
+		}
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, dialect.Release(name)); err != nil {
+		return fmt.Errorf("finalizeSavepoint release error: %w", err)
+	}
+	return nil
+}
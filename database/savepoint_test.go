@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/database/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingTx is a fake transaction that records executed statements.
+type recordingTx struct {
+	FakeTx
+	statements []string
+	execErrs   map[string]error
+}
+
+func (r *recordingTx) ExecContext(
+	_ context.Context, query string, args ...any,
+) (types.Result, error) {
+	r.statements = append(r.statements, query)
+	if err, ok := r.execErrs[query]; ok {
+		return nil, err
+	}
+	return &fakeResult{}, nil
+}
+
+// upperDialect renders savepoint statements in a custom syntax.
+type upperDialect struct{}
+
+func (upperDialect) Savepoint(name string) string  { return "SP " + name }
+func (upperDialect) Release(name string) string    { return "REL " + name }
+func (upperDialect) RollbackTo(name string) string { return "RB " + name }
+
+// SavepointTestSuite is a test suite for savepoints.
+type SavepointTestSuite struct {
+	suite.Suite
+}
+
+// TestSavepointTestSuite runs the test suite.
+func TestSavepointTestSuite(t *testing.T) {
+	suite.Run(t, new(SavepointTestSuite))
+}
+
+// Test_Savepoint tests the statements issued for the savepoint outcomes.
+func (s *SavepointTestSuite) Test_Savepoint() {
+	fnErr := errors.New("fn error")
+	testCases := []struct {
+		name       string
+		dialect    types.SavepointDialect
+		fn         types.TxFn[int]
+		execErrs   map[string]error
+		wantResult int
+		wantErr    error
+		wantStmts  []string
+	}{
+		{
+			name: "success releases",
+			fn: func(ctx context.Context, tx types.Tx) (int, error) {
+				return 7, nil
+			},
+			wantResult: 7,
+			wantStmts:  []string{"SAVEPOINT sp1", "RELEASE SAVEPOINT sp1"},
+		},
+		{
+			name: "error rolls back",
+			fn: func(ctx context.Context, tx types.Tx) (int, error) {
+				return 0, fnErr
+			},
+			wantErr: fnErr,
+			wantStmts: []string{
+				"SAVEPOINT sp1", "ROLLBACK TO SAVEPOINT sp1",
+			},
+		},
+		{
+			name: "rollback error keeps original error",
+			fn: func(ctx context.Context, tx types.Tx) (int, error) {
+				return 0, fnErr
+			},
+			execErrs: map[string]error{
+				"ROLLBACK TO SAVEPOINT sp1": errors.New("rollback failed"),
+			},
+			wantErr: fnErr,
+			wantStmts: []string{
+				"SAVEPOINT sp1", "ROLLBACK TO SAVEPOINT sp1",
+			},
+		},
+		{
+			name: "release error",
+			fn: func(ctx context.Context, tx types.Tx) (int, error) {
+				return 7, nil
+			},
+			execErrs: map[string]error{
+				"RELEASE SAVEPOINT sp1": errors.New("release failed"),
+			},
+			wantErr:   errAny,
+			wantStmts: []string{"SAVEPOINT sp1", "RELEASE SAVEPOINT sp1"},
+		},
+		{
+			name: "create error skips fn",
+			fn: func(ctx context.Context, tx types.Tx) (int, error) {
+				panic("should not run")
+			},
+			execErrs: map[string]error{
+				"SAVEPOINT sp1": errors.New("create failed"),
+			},
+			wantErr:   errAny,
+			wantStmts: []string{"SAVEPOINT sp1"},
+		},
+		{
+			name:    "custom dialect",
+			dialect: upperDialect{},
+			fn: func(ctx context.Context, tx types.Tx) (int, error) {
+				return 1, nil
+			},
+			wantResult: 1,
+			wantStmts:  []string{"SP sp1", "REL sp1"},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			tx := &recordingTx{execErrs: tc.execErrs}
+			var result int
+			var err error
+			if tc.dialect != nil {
+				result, err = SavepointWithDialect(
+					context.Background(), tx, tc.dialect, "sp1", tc.fn,
+				)
+			} else {
+				result, err = Savepoint(context.Background(), tx, "sp1", tc.fn)
+			}
+			switch tc.wantErr {
+			case nil:
+				require.NoError(s.T(), err)
+			case errAny:
+				require.Error(s.T(), err)
+			default:
+				require.ErrorIs(s.T(), err, tc.wantErr)
+			}
+			assert.Equal(s.T(), tc.wantResult, result)
+			assert.Equal(s.T(), tc.wantStmts, tx.statements)
+			assert.False(s.T(), tx.commitCalled || tx.rollbackCalled)
+		})
+	}
+}
+
+// Test_Savepoint_Panic tests that a panic rolls back to the savepoint and is
+// propagated.
+func (s *SavepointTestSuite) Test_Savepoint_Panic() {
+	tx := &recordingTx{}
+	assert.PanicsWithValue(s.T(), "boom", func() {
+		_, _ = Savepoint(
+			context.Background(), tx, "sp1",
+			func(ctx context.Context, tx types.Tx) (int, error) {
+				panic("boom")
+			},
+		)
+	})
+	assert.Equal(
+		s.T(),
+		[]string{"SAVEPOINT sp1", "ROLLBACK TO SAVEPOINT sp1"},
+		tx.statements,
+	)
+}
+
+// Test_Savepoint_InvalidName tests that invalid names are rejected before any
+// statement is issued.
+func (s *SavepointTestSuite) Test_Savepoint_InvalidName() {
+	tx := &recordingTx{}
+	_, err := Savepoint(
+		context.Background(), tx, "sp1; DROP TABLE users",
+		func(ctx context.Context, tx types.Tx) (int, error) {
+			return 0, nil
+		},
+	)
+	require.Error(s.T(), err)
+	assert.Empty(s.T(), tx.statements)
+}
+
+// errAny is a marker for test cases that expect any error.
+var errAny = errors.New("any error")
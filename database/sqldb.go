@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pureapi/pureapi-core/database/types"
@@ -54,12 +55,52 @@ func NewSQLDBAdapter(driver string, dsn string) (types.DB, error) {
 	return db, err
 }
 
-// Ping sends a ping to the database to check if it is alive.
+// Ping sends a ping to the database to check if it is alive. It calls
+// PingContext with a background context.
 //
 // Returns:
 //   - error: An error if the ping fails.
 func (db *sqlDB) Ping() error {
-	return db.DB.Ping()
+	return db.PingContext(context.Background())
+}
+
+// PingContext sends a ping to the database to check if it is alive. It
+// returns when the context is done.
+//
+// Parameters:
+//   - ctx: The context for the ping.
+//
+// Returns:
+//   - error: An error if the ping fails.
+func (db *sqlDB) PingContext(ctx context.Context) error {
+	return db.DB.PingContext(ctx)
+}
+
+// DefaultPingCheckTimeout is the deadline used by PingCheck when no timeout
+// is given.
+const DefaultPingCheckTimeout = 2 * time.Second
+
+// PingCheck returns a health check function that pings db with a deadline.
+// It fits the Check field of server.HealthCheck for readiness checks.
+//
+// Parameters:
+//   - db: The database connection.
+//   - timeout: The ping deadline. If not positive, DefaultPingCheckTimeout is
+//     used.
+//
+// Returns:
+//   - func(ctx context.Context) error: The health check function.
+func PingCheck(
+	db types.DB, timeout time.Duration,
+) func(ctx context.Context) error {
+	if timeout <= 0 {
+		timeout = DefaultPingCheckTimeout
+	}
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return db.PingContext(ctx)
+	}
 }
 
 // SetConnMaxLifetime sets the maximum time a connection may be reused.
@@ -94,6 +135,37 @@ func (db *sqlDB) SetMaxIdleConns(n int) {
 	db.DB.SetMaxIdleConns(n)
 }
 
+// Stats returns the connection pool statistics of the database.
+//
+// Returns:
+//   - sql.DBStats: The connection pool statistics.
+func (db *sqlDB) Stats() sql.DBStats {
+	return db.DB.Stats()
+}
+
+// statsProvider is implemented by connections that report connection pool
+// statistics, such as the connections returned by Connect and ReplicaDB.
+type statsProvider interface {
+	Stats() sql.DBStats
+}
+
+// Stats returns the connection pool statistics of db for monitoring. The
+// statistics are only available if db has a Stats method.
+//
+// Parameters:
+//   - db: The database connection.
+//
+// Returns:
+//   - sql.DBStats: The connection pool statistics.
+//   - bool: Whether db reports statistics.
+func Stats(db types.DB) (sql.DBStats, bool) {
+	provider, ok := db.(statsProvider)
+	if !ok {
+		return sql.DBStats{}, false
+	}
+	return provider.Stats(), true
+}
+
 // Prepare creates a prepared statement for later queries or executions.
 //
 // Parameters:
@@ -103,7 +175,23 @@ func (db *sqlDB) SetMaxIdleConns(n int) {
 //   - Stmt: The prepared statement.
 //   - error: An error if the statement cannot be prepared.
 func (db *sqlDB) Prepare(query string) (types.Stmt, error) {
-	stmt, err := db.DB.Prepare(query)
+	return db.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext creates a prepared statement for later queries or executions
+// using the provided context.
+//
+// Parameters:
+//   - ctx: The context for the preparation.
+//   - query: The SQL query string to prepare.
+//
+// Returns:
+//   - Stmt: The prepared statement.
+//   - error: An error if the statement cannot be prepared.
+func (db *sqlDB) PrepareContext(
+	ctx context.Context, query string,
+) (types.Stmt, error) {
+	stmt, err := db.DB.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("DB.Prepare error: %w", err)
 	}
@@ -139,7 +227,24 @@ func (db *sqlDB) BeginTx(
 //   - Result: The result of the query.
 //   - error: An error if the query fails.
 func (db *sqlDB) Exec(query string, args ...any) (types.Result, error) {
-	res, err := db.DB.Exec(query, args...)
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext executes a query without returning rows using the provided
+// context.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Result: The result of the query.
+//   - error: An error if the query fails.
+func (db *sqlDB) ExecContext(
+	ctx context.Context, query string, args ...any,
+) (types.Result, error) {
+	res, err := db.DB.ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("DB.Exec error: %w", err)
 	}
@@ -156,7 +261,23 @@ func (db *sqlDB) Exec(query string, args ...any) (types.Result, error) {
 //   - Rows: The rows of the query.
 //   - error: An error if the query fails.
 func (db *sqlDB) Query(query string, args ...any) (types.Rows, error) {
-	rows, err := db.DB.Query(query, args...)
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query that returns rows using the provided context.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Rows: The rows of the query.
+//   - error: An error if the query fails.
+func (db *sqlDB) QueryContext(
+	ctx context.Context, query string, args ...any,
+) (types.Rows, error) {
+	rows, err := db.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("DB.Query error: %w", err)
 	}
@@ -172,7 +293,23 @@ func (db *sqlDB) Query(query string, args ...any) (types.Rows, error) {
 // Returns:
 //   - Row: The row of the query.
 func (db *sqlDB) QueryRow(query string, args ...any) types.Row {
-	return db.DB.QueryRow(query, args...)
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a query that returns a single row using the
+// provided context.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Row: The row of the query.
+func (db *sqlDB) QueryRowContext(
+	ctx context.Context, query string, args ...any,
+) types.Row {
+	return &RealRow{Row: db.DB.QueryRowContext(ctx, query, args...)}
 }
 
 // RealStmt wraps *sql.Stmt to implement the Stmt interface.
@@ -200,7 +337,20 @@ func (s *RealStmt) Close() error {
 // Returns:
 //   - Row: The row of the query.
 func (s *RealStmt) QueryRow(args ...any) types.Row {
-	return s.Stmt.QueryRow(args...)
+	return s.QueryRowContext(context.Background(), args...)
+}
+
+// QueryRowContext executes a prepared query statement with the given context
+// and arguments.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Row: The row of the query.
+func (s *RealStmt) QueryRowContext(ctx context.Context, args ...any) types.Row {
+	return &RealRow{Row: s.Stmt.QueryRowContext(ctx, args...)}
 }
 
 // Exec executes a prepared statement with the given arguments.
@@ -211,7 +361,22 @@ func (s *RealStmt) QueryRow(args ...any) types.Row {
 // Returns:
 //   - Result: The result of the query.
 func (s *RealStmt) Exec(args ...any) (types.Result, error) {
-	res, err := s.Stmt.Exec(args...)
+	return s.ExecContext(context.Background(), args...)
+}
+
+// ExecContext executes a prepared statement with the given context and
+// arguments.
+//
+// Parameters:
+//   - ctx: The context for the execution.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Result: The result of the query.
+func (s *RealStmt) ExecContext(
+	ctx context.Context, args ...any,
+) (types.Result, error) {
+	res, err := s.Stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("Stmt.Exec error: %w", err)
 	}
@@ -226,7 +391,22 @@ func (s *RealStmt) Exec(args ...any) (types.Result, error) {
 // Returns:
 //   - Rows: The rows of the query.
 func (s *RealStmt) Query(args ...any) (types.Rows, error) {
-	rows, err := s.Stmt.Query(args...)
+	return s.QueryContext(context.Background(), args...)
+}
+
+// QueryContext executes a prepared query statement with the given context and
+// arguments.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Rows: The rows of the query.
+func (s *RealStmt) QueryContext(
+	ctx context.Context, args ...any,
+) (types.Rows, error) {
+	rows, err := s.Stmt.QueryContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("Stmt.Query error: %w", err)
 	}
@@ -238,7 +418,8 @@ type RealRows struct {
 	*sql.Rows
 }
 
-// Scan scans the rows into dest.
+// Scan scans the rows into dest. Errors include the number of destinations
+// and the column names of the rows.
 //
 // Parameters:
 //   - dest: The destination slice to scan into.
@@ -248,7 +429,10 @@ type RealRows struct {
 func (r *RealRows) Scan(dest ...any) error {
 	err := r.Rows.Scan(dest...)
 	if err != nil {
-		return fmt.Errorf("Rows.Scan error: %w", err)
+		columns, _ := r.Rows.Columns()
+		return fmt.Errorf(
+			"Rows.Scan error (%s): %w", scanErrorContext(dest, columns), err,
+		)
 	}
 	return nil
 }
@@ -285,12 +469,26 @@ func (r *RealRows) Err() error {
 	return nil
 }
 
+// Columns returns the column names of the rows.
+//
+// Returns:
+//   - []string: The column names.
+//   - error: An error if the rows are closed.
+func (r *RealRows) Columns() ([]string, error) {
+	columns, err := r.Rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("Rows.Columns error: %w", err)
+	}
+	return columns, nil
+}
+
 // RealRow wraps *sql.Row to implement the Row interface.
 type RealRow struct {
 	*sql.Row
 }
 
-// Scan scans the row into dest.
+// Scan scans the row into dest. Errors include the number of destinations,
+// as sql.Row does not expose its columns.
 //
 // Parameters:
 //   - dest: The destination slice to scan into.
@@ -300,11 +498,25 @@ type RealRow struct {
 func (r *RealRow) Scan(dest ...any) error {
 	err := r.Row.Scan(dest...)
 	if err != nil {
-		return fmt.Errorf("Row.Scan error: %w", err)
+		return fmt.Errorf(
+			"Row.Scan error (%s): %w", scanErrorContext(dest, nil), err,
+		)
 	}
 	return nil
 }
 
+// scanErrorContext describes the scan destinations and the columns, if
+// known, for scan error messages.
+func scanErrorContext(dest []any, columns []string) string {
+	if len(columns) == 0 {
+		return fmt.Sprintf("%d destinations", len(dest))
+	}
+	return fmt.Sprintf(
+		"%d destinations, columns: %s",
+		len(dest), strings.Join(columns, ", "),
+	)
+}
+
 // RealResult wraps sql.Result to implement the Result interface.
 type RealResult struct {
 	Result sql.Result
@@ -372,7 +584,22 @@ func (tx *RealTx) Rollback() error {
 //   - Stmt: The prepared statement.
 //   - error: An error if the statement cannot be prepared.
 func (tx *RealTx) Prepare(query string) (types.Stmt, error) {
-	stmt, err := tx.Tx.Prepare(query)
+	return tx.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext prepares the statement using the provided context.
+//
+// Parameters:
+//   - ctx: The context for the preparation.
+//   - query: The SQL query string to prepare.
+//
+// Returns:
+//   - Stmt: The prepared statement.
+//   - error: An error if the statement cannot be prepared.
+func (tx *RealTx) PrepareContext(
+	ctx context.Context, query string,
+) (types.Stmt, error) {
+	stmt, err := tx.Tx.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("Tx.Prepare error: %w", err)
 	}
@@ -389,7 +616,24 @@ func (tx *RealTx) Prepare(query string) (types.Stmt, error) {
 //   - Result: The result of the query.
 //   - error: An error if the query cannot be executed.
 func (tx *RealTx) Exec(query string, args ...any) (types.Result, error) {
-	res, err := tx.Tx.Exec(query, args...)
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext executes a query without returning rows using the provided
+// context.
+//
+// Parameters:
+//   - ctx: The context for the query.
+//   - query: The SQL query string to execute.
+//   - args: The query parameters.
+//
+// Returns:
+//   - Result: The result of the query.
+//   - error: An error if the query cannot be executed.
+func (tx *RealTx) ExecContext(
+	ctx context.Context, query string, args ...any,
+) (types.Result, error) {
+	res, err := tx.Tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("Tx.Exec error: %w", err)
 	}
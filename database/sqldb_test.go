@@ -6,6 +6,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,61 @@ func (s *SQLDBTestSuite) TestSQLDB_Ping() {
 	require.NoError(s.T(), mock.ExpectationsWereMet())
 }
 
+// Test_PingContext_Cancelled verifies that PingContext returns promptly when
+// the context is cancelled.
+func (s *SQLDBTestSuite) Test_PingContext_Cancelled() {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(s.T(), err)
+	defer db.Close()
+	sqlDB := &sqlDB{DB: db}
+	mock.ExpectPing().WillDelayFor(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+	require.Error(s.T(), err)
+	assert.Less(s.T(), time.Since(start), 500*time.Millisecond)
+}
+
+// Test_PingCheck verifies that PingCheck applies its deadline.
+func (s *SQLDBTestSuite) Test_PingCheck() {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(s.T(), err)
+	defer db.Close()
+	sqlDB := &sqlDB{DB: db}
+
+	mock.ExpectPing()
+	require.NoError(s.T(), PingCheck(sqlDB, 0)(context.Background()))
+
+	mock.ExpectPing().WillDelayFor(time.Second)
+	start := time.Now()
+	err = PingCheck(sqlDB, 10*time.Millisecond)(context.Background())
+	require.Error(s.T(), err)
+	assert.Less(s.T(), time.Since(start), 500*time.Millisecond)
+}
+
+// Test_Stats verifies that Stats returns the pool statistics of the
+// underlying database.
+func (s *SQLDBTestSuite) Test_Stats() {
+	db, _, err := sqlmock.New()
+	require.NoError(s.T(), err)
+	defer db.Close()
+	sqlDB := &sqlDB{DB: db}
+	sqlDB.SetMaxOpenConns(7)
+
+	stats, ok := Stats(sqlDB)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 7, stats.MaxOpenConnections)
+
+	// Connections without a Stats method report no statistics.
+	stats, ok = Stats(&FakeDB{})
+	assert.False(s.T(), ok)
+	assert.Equal(s.T(), sql.DBStats{}, stats)
+	_, ok = Stats(nil)
+	assert.False(s.T(), ok)
+}
+
 // Test_PrepareAndExec verifies that Prepare returns a RealStmt and that Exec
 // works.
 func (s *SQLDBTestSuite) Test_PrepareAndExec() {
@@ -95,6 +151,24 @@ func (s *SQLDBTestSuite) Test_Exec() {
 	require.NoError(s.T(), mock.ExpectationsWereMet())
 }
 
+// Test_ExecContext_Cancelled verifies that ExecContext and QueryContext
+// propagate the context to the driver.
+func (s *SQLDBTestSuite) Test_ExecContext_Cancelled() {
+	db, mock, err := sqlmock.New()
+	require.NoError(s.T(), err)
+	defer db.Close()
+	sqlDB := &sqlDB{DB: db}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = sqlDB.ExecContext(ctx, "UPDATE test SET name = ?", "new")
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	_, err = sqlDB.QueryContext(ctx, "SELECT id FROM test")
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	require.NoError(s.T(), mock.ExpectationsWereMet())
+}
+
 // Test_Query verifies that Query and QueryRow work.
 func (s *SQLDBTestSuite) Test_QueryAndQueryRow() {
 	// Test the Query method of SQLDB.
@@ -136,6 +210,42 @@ func (s *SQLDBTestSuite) Test_QueryAndQueryRow() {
 	require.NoError(s.T(), mock.ExpectationsWereMet())
 }
 
+// Test_ScanErrorContext verifies that scan errors include the destination
+// count and, for rows, the column names while still wrapping the cause.
+func (s *SQLDBTestSuite) Test_ScanErrorContext() {
+	db, mock, err := sqlmock.New()
+	require.NoError(s.T(), err)
+	defer db.Close()
+	sqlDB := &sqlDB{DB: db}
+
+	query := "SELECT id, name FROM test"
+	mock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name"}).AddRow("abc", "Alice"),
+	)
+	resultRows, err := sqlDB.Query(query)
+	require.NoError(s.T(), err)
+	defer resultRows.Close()
+	require.True(s.T(), resultRows.Next())
+	var id int
+	var name string
+	err = resultRows.Scan(&id, &name)
+	require.Error(s.T(), err)
+	assert.Contains(
+		s.T(), err.Error(),
+		"Rows.Scan error (2 destinations, columns: id, name): ",
+	)
+	assert.Contains(s.T(), err.Error(), "column index 0")
+
+	mock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name"}),
+	)
+	err = sqlDB.QueryRow(query).Scan(&id, &name)
+	require.Error(s.T(), err)
+	assert.ErrorIs(s.T(), err, sql.ErrNoRows)
+	assert.Contains(s.T(), err.Error(), "Row.Scan error (2 destinations): ")
+	require.NoError(s.T(), mock.ExpectationsWereMet())
+}
+
 // Test_BeginTxAndCommit tests starting a transaction and committing.
 func (s *SQLDBTestSuite) Test_BeginTxAndCommit() {
 	// Test starting a transaction and committing.
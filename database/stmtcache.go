@@ -0,0 +1,217 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/pureapi/pureapi-core/database/types"
+)
+
+// DefaultStmtCacheSize is the cache size used when no valid size is given.
+const DefaultStmtCacheSize = 100
+
+// CachingPreparer wraps a Preparer and reuses prepared statements by query
+// string. The cache is bounded and evicts the least recently used statement
+// when full. Statements returned by the cache are shared, so closing them does
+// not close the underlying statement. The underlying statement is closed once
+// it has been evicted and every caller has closed its handle.
+//
+// Prepared statements are tied to the preparer that created them. A *sql.DB
+// transparently re-prepares statements on other pool connections, but
+// statements prepared on a transaction, or by drivers that bind statements to
+// a single connection, become invalid once that transaction or connection is
+// gone. Only wrap preparers whose statements outlive the cache.
+type CachingPreparer struct {
+	preparer types.Preparer
+	maxSize  int
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List // Most recently used entries are at the front.
+}
+
+// CachingPreparer implements the Preparer interface.
+var _ types.Preparer = (*CachingPreparer)(nil)
+
+// stmtCacheEntry is a cached statement and its usage state.
+type stmtCacheEntry struct {
+	query   string
+	stmt    types.Stmt
+	refs    int  // Number of handles not yet closed.
+	evicted bool // Whether the entry has been removed from the cache.
+}
+
+// NewCachingPreparer creates a new CachingPreparer. If maxSize is less than 1,
+// DefaultStmtCacheSize is used.
+//
+// Parameters:
+//   - preparer: The preparer to prepare statements with.
+//   - maxSize: The maximum number of cached statements.
+//
+// Returns:
+//   - *CachingPreparer: A new CachingPreparer instance.
+func NewCachingPreparer(
+	preparer types.Preparer, maxSize int,
+) *CachingPreparer {
+	if maxSize < 1 {
+		maxSize = DefaultStmtCacheSize
+	}
+	return &CachingPreparer{
+		preparer: preparer,
+		maxSize:  maxSize,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Prepare returns a cached statement for the query, preparing it if needed.
+//
+// Parameters:
+//   - query: The SQL query string to prepare.
+//
+// Returns:
+//   - Stmt: The prepared statement.
+//   - error: An error if the statement cannot be prepared.
+func (c *CachingPreparer) Prepare(query string) (types.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext returns a cached statement for the query, preparing it with
+// the provided context if needed. The lock is not held while preparing, so a
+// slow prepare does not block other queries. If another caller cached the
+// same query in the meantime, its statement is used and the duplicate is
+// closed.
+//
+// Parameters:
+//   - ctx: The context for the preparation.
+//   - query: The SQL query string to prepare.
+//
+// Returns:
+//   - Stmt: The prepared statement.
+//   - error: An error if the statement cannot be prepared.
+func (c *CachingPreparer) PrepareContext(
+	ctx context.Context, query string,
+) (types.Stmt, error) {
+	if stmt := c.cached(query); stmt != nil {
+		return stmt, nil
+	}
+	stmt, err := c.preparer.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if elem, ok := c.entries[query]; ok {
+		c.order.MoveToFront(elem)
+		handle := c.acquire(elem.Value.(*stmtCacheEntry))
+		c.mu.Unlock()
+		_ = stmt.Close()
+		return handle, nil
+	}
+	defer c.mu.Unlock()
+	entry := &stmtCacheEntry{query: query, stmt: stmt}
+	c.entries[query] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxSize {
+		c.evict(c.order.Back())
+	}
+	return c.acquire(entry), nil
+}
+
+// cached returns a new handle for the cached statement of the query, or nil
+// if the query is not cached.
+func (c *CachingPreparer) cached(query string) *cachedStmt {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[query]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return c.acquire(elem.Value.(*stmtCacheEntry))
+}
+
+// Len returns the number of cached statements.
+//
+// Returns:
+//   - int: The number of cached statements.
+func (c *CachingPreparer) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Close evicts all cached statements. Statements that are not in use are
+// closed immediately, the rest are closed when their last handle is closed.
+// The preparer remains usable after Close.
+//
+// Returns:
+//   - error: An error joining all errors encountered while closing.
+func (c *CachingPreparer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for c.order.Len() > 0 {
+		if err := c.evict(c.order.Back()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"CachingPreparer.Close error: %w", errors.Join(errs...),
+		)
+	}
+	return nil
+}
+
+// acquire returns a new handle for the entry. Must be called with the lock.
+func (c *CachingPreparer) acquire(entry *stmtCacheEntry) *cachedStmt {
+	entry.refs++
+	return &cachedStmt{Stmt: entry.stmt, cache: c, entry: entry}
+}
+
+// release releases a handle of the entry and closes the statement if the entry
+// is evicted and no longer in use.
+func (c *CachingPreparer) release(entry *stmtCacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		return entry.stmt.Close()
+	}
+	return nil
+}
+
+// evict removes the element from the cache and closes its statement if it is
+// not in use. Must be called with the lock.
+func (c *CachingPreparer) evict(elem *list.Element) error {
+	entry := c.order.Remove(elem).(*stmtCacheEntry)
+	delete(c.entries, entry.query)
+	entry.evicted = true
+	if entry.refs == 0 {
+		return entry.stmt.Close()
+	}
+	return nil
+}
+
+// cachedStmt is a handle to a cached statement. Closing it releases the handle
+// instead of closing the underlying statement.
+type cachedStmt struct {
+	types.Stmt
+	cache *CachingPreparer
+	entry *stmtCacheEntry
+	once  sync.Once
+}
+
+// Close releases the handle. The underlying statement is closed only if it has
+// been evicted from the cache and no other handles are in use.
+//
+// Returns:
+//   - error: An error if the underlying statement cannot be closed.
+func (s *cachedStmt) Close() error {
+	var err error
+	s.once.Do(func() {
+		err = s.cache.release(s.entry)
+	})
+	return err
+}
@@ -0,0 +1,183 @@
+package database
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/database/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// CachingPreparerTestSuite is a test suite for CachingPreparer.
+type CachingPreparerTestSuite struct {
+	suite.Suite
+	mu       sync.Mutex
+	prepared map[string]int
+	closed   map[string]int
+	preparer *fakePreparer
+}
+
+// TestCachingPreparerTestSuite runs the test suite.
+func TestCachingPreparerTestSuite(t *testing.T) {
+	suite.Run(t, new(CachingPreparerTestSuite))
+}
+
+// SetupTest creates a preparer that counts prepares and closes per query.
+func (s *CachingPreparerTestSuite) SetupTest() {
+	s.prepared = map[string]int{}
+	s.closed = map[string]int{}
+	s.preparer = &fakePreparer{
+		prepareFunc: func(query string) (types.Stmt, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.prepared[query]++
+			return &fakeStmt{
+				closeFunc: func() error {
+					s.mu.Lock()
+					defer s.mu.Unlock()
+					s.closed[query]++
+					return nil
+				},
+			}, nil
+		},
+	}
+}
+
+// Test_Reuse tests that the same query is prepared only once and that closing
+// a handle does not close the statement.
+func (s *CachingPreparerTestSuite) Test_Reuse() {
+	cache := NewCachingPreparer(s.preparer, 2)
+	for range 3 {
+		stmt, err := cache.Prepare("SELECT 1")
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), stmt.Close())
+	}
+	assert.Equal(s.T(), 1, s.prepared["SELECT 1"])
+	assert.Equal(s.T(), 0, s.closed["SELECT 1"])
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+// Test_EvictsLeastRecentlyUsed tests that the least recently used statement
+// is closed when the cache is full.
+func (s *CachingPreparerTestSuite) Test_EvictsLeastRecentlyUsed() {
+	cache := NewCachingPreparer(s.preparer, 2)
+	for _, query := range []string{"A", "B", "A", "C"} {
+		stmt, err := cache.Prepare(query)
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), stmt.Close())
+	}
+	assert.Equal(s.T(), 2, cache.Len())
+	assert.Equal(s.T(), 1, s.closed["B"], "B should be evicted")
+	assert.Equal(s.T(), 0, s.closed["A"])
+	assert.Equal(s.T(), 0, s.closed["C"])
+}
+
+// Test_EvictInUse tests that an evicted statement still in use is closed only
+// once its handle is closed.
+func (s *CachingPreparerTestSuite) Test_EvictInUse() {
+	cache := NewCachingPreparer(s.preparer, 1)
+	stmtA, err := cache.Prepare("A")
+	require.NoError(s.T(), err)
+	stmtB, err := cache.Prepare("B")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, s.closed["A"], "A is still in use")
+	require.NoError(s.T(), stmtA.Close())
+	require.NoError(s.T(), stmtA.Close(), "closing twice is a no-op")
+	assert.Equal(s.T(), 1, s.closed["A"])
+	require.NoError(s.T(), stmtB.Close())
+	assert.Equal(s.T(), 0, s.closed["B"])
+}
+
+// Test_Close tests that Close drains the cache and closes all statements.
+func (s *CachingPreparerTestSuite) Test_Close() {
+	cache := NewCachingPreparer(s.preparer, 0)
+	for _, query := range []string{"A", "B"} {
+		stmt, err := cache.Prepare(query)
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), stmt.Close())
+	}
+	require.NoError(s.T(), cache.Close())
+	assert.Equal(s.T(), 0, cache.Len())
+	assert.Equal(s.T(), 1, s.closed["A"])
+	assert.Equal(s.T(), 1, s.closed["B"])
+}
+
+// Test_Concurrent tests that the cache is safe for concurrent use.
+func (s *CachingPreparerTestSuite) Test_Concurrent() {
+	cache := NewCachingPreparer(s.preparer, 2)
+	queries := []string{"A", "B", "C"}
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func(query string) {
+			defer wg.Done()
+			stmt, err := cache.Prepare(query)
+			if err == nil {
+				_, _ = stmt.Exec()
+				_ = stmt.Close()
+			}
+		}(queries[i%len(queries)])
+	}
+	wg.Wait()
+	require.NoError(s.T(), cache.Close())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, query := range queries {
+		assert.Equal(
+			s.T(), s.prepared[query], s.closed[query],
+			"every prepared statement should be closed exactly once",
+		)
+	}
+}
+
+// Test_PrepareWithoutLock tests that a slow prepare does not block cached
+// queries and that a duplicate prepared concurrently is closed.
+func (s *CachingPreparerTestSuite) Test_PrepareWithoutLock() {
+	started := make(chan struct{}, 2)
+	unblock := make(chan struct{})
+	prepare := s.preparer.prepareFunc
+	s.preparer.prepareFunc = func(query string) (types.Stmt, error) {
+		if query == "slow" {
+			started <- struct{}{}
+			<-unblock
+		}
+		return prepare(query)
+	}
+	cache := NewCachingPreparer(s.preparer, 10)
+	fast, err := cache.Prepare("fast")
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), fast.Close())
+
+	var wg sync.WaitGroup
+	handles := make([]types.Stmt, 2)
+	for i := range handles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handles[i], _ = cache.Prepare("slow")
+		}()
+	}
+	<-started
+	<-started
+
+	// The cached query is served while the slow prepares are running.
+	fast, err = cache.Prepare("fast")
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), fast.Close())
+
+	close(unblock)
+	wg.Wait()
+	s.Equal(2, cache.Len())
+	s.Same(
+		handles[0].(*cachedStmt).entry, handles[1].(*cachedStmt).entry,
+	)
+	s.mu.Lock()
+	s.Equal(2, s.prepared["slow"])
+	s.Equal(1, s.closed["slow"])
+	s.mu.Unlock()
+	for _, handle := range handles {
+		require.NoError(s.T(), handle.Close())
+	}
+}
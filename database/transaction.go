@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/pureapi/pureapi-core/database/types"
@@ -57,3 +58,33 @@ func finalizeTransaction(tx types.Tx, txErr error) error {
 	}
 	return nil
 }
+
+// WithTx begins a transaction on db and executes txFn within it using
+// Transaction. The transaction is committed if txFn succeeds and rolled back
+// if it returns an error or panics. Panics are propagated after the rollback.
+//
+// Parameters:
+//   - ctx: The context for the transaction.
+//   - db: The database to begin the transaction on.
+//   - opts: The transaction options, or nil for the defaults.
+//   - txFn: The function to execute in a transaction.
+//
+// Returns:
+//   - Result: The result of the transactional function.
+//   - error: An error if the transaction can not be begun or fails.
+func WithTx[Result any](
+	ctx context.Context,
+	db types.DB,
+	opts *sql.TxOptions,
+	txFn types.TxFn[Result],
+) (Result, error) {
+	var zero Result
+	if db == nil {
+		return zero, fmt.Errorf("WithTx: db is nil")
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return zero, fmt.Errorf("WithTx: begin transaction: %w", err)
+	}
+	return Transaction(ctx, tx, txFn)
+}
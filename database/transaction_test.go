@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -38,6 +39,36 @@ func (f *FakeTx) Exec(query string, args ...any) (types.Result, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (f *FakeTx) PrepareContext(
+	ctx context.Context, query string,
+) (types.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *FakeTx) ExecContext(
+	ctx context.Context, query string, args ...any,
+) (types.Result, error) {
+	return nil, errors.New("not implemented")
+}
+
+// txDB is a fake database whose BeginTx returns a fixed transaction.
+type txDB struct {
+	fakeDB
+	tx       types.Tx
+	beginErr error
+	opts     *sql.TxOptions
+}
+
+func (d *txDB) BeginTx(
+	_ context.Context, opts *sql.TxOptions,
+) (types.Tx, error) {
+	d.opts = opts
+	if d.beginErr != nil {
+		return nil, d.beginErr
+	}
+	return d.tx, nil
+}
+
 // TransactionTestSuite is a test suite for transaction-related tests.
 type TransactionTestSuite struct {
 	suite.Suite
@@ -162,3 +193,76 @@ func (s *TransactionTestSuite) TestTransaction_RollbackError() {
 	)
 	assert.Equal(s.T(), 0, res)
 }
+
+// TestWithTx_Commit verifies that WithTx begins a transaction with the given
+// options and commits it when txFn succeeds.
+func (s *TransactionTestSuite) TestWithTx_Commit() {
+	fakeTx := &FakeTx{}
+	db := &txDB{tx: fakeTx}
+	opts := &sql.TxOptions{ReadOnly: true}
+	txFn := func(ctx context.Context, tx types.Tx) (int, error) {
+		assert.Same(s.T(), fakeTx, tx)
+		return 7, nil
+	}
+	res, err := WithTx(context.Background(), db, opts, txFn)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 7, res)
+	assert.Same(s.T(), opts, db.opts)
+	assert.True(s.T(), fakeTx.commitCalled)
+	assert.False(s.T(), fakeTx.rollbackCalled)
+}
+
+// TestWithTx_Rollback verifies that WithTx rolls back when txFn returns an
+// error.
+func (s *TransactionTestSuite) TestWithTx_Rollback() {
+	fakeTx := &FakeTx{}
+	txFnErr := errors.New("txFn error")
+	txFn := func(ctx context.Context, tx types.Tx) (int, error) {
+		return 0, txFnErr
+	}
+	_, err := WithTx(context.Background(), &txDB{tx: fakeTx}, nil, txFn)
+	require.ErrorIs(s.T(), err, txFnErr)
+	assert.True(s.T(), fakeTx.rollbackCalled)
+	assert.False(s.T(), fakeTx.commitCalled)
+}
+
+// TestWithTx_Panic verifies that WithTx rolls back and re-panics when txFn
+// panics.
+func (s *TransactionTestSuite) TestWithTx_Panic() {
+	fakeTx := &FakeTx{}
+	txFn := func(ctx context.Context, tx types.Tx) (int, error) {
+		panic("boom")
+	}
+	assert.PanicsWithValue(s.T(), "boom", func() {
+		_, _ = WithTx(context.Background(), &txDB{tx: fakeTx}, nil, txFn)
+	})
+	assert.True(s.T(), fakeTx.rollbackCalled)
+	assert.False(s.T(), fakeTx.commitCalled)
+}
+
+// TestWithTx_BeginError verifies that WithTx returns the begin error without
+// calling txFn.
+func (s *TransactionTestSuite) TestWithTx_BeginError() {
+	beginErr := errors.New("begin failed")
+	called := false
+	txFn := func(ctx context.Context, tx types.Tx) (int, error) {
+		called = true
+		return 0, nil
+	}
+	_, err := WithTx(
+		context.Background(), &txDB{beginErr: beginErr}, nil, txFn,
+	)
+	require.ErrorIs(s.T(), err, beginErr)
+	assert.Contains(s.T(), err.Error(), "WithTx: begin transaction")
+	assert.False(s.T(), called)
+}
+
+// TestWithTx_NilDB verifies that WithTx returns an error for a nil db.
+func (s *TransactionTestSuite) TestWithTx_NilDB() {
+	txFn := func(ctx context.Context, tx types.Tx) (int, error) {
+		return 0, nil
+	}
+	_, err := WithTx(context.Background(), nil, nil, txFn)
+	require.Error(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "WithTx: db is nil")
+}
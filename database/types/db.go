@@ -10,20 +10,25 @@ import (
 type DB interface {
 	Preparer
 	Ping() error
+	PingContext(ctx context.Context) error
 	SetConnMaxLifetime(d time.Duration)
 	SetConnMaxIdleTime(d time.Duration)
 	SetMaxOpenConns(n int)
 	SetMaxIdleConns(n int)
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
 	Exec(query string, args ...any) (Result, error)
+	ExecContext(ctx context.Context, query string, args ...any) (Result, error)
 	Query(query string, args ...any) (Rows, error)
+	QueryContext(ctx context.Context, query string, args ...any) (Rows, error)
 	QueryRow(query string, args ...any) Row
+	QueryRowContext(ctx context.Context, query string, args ...any) Row
 	Close() error
 }
 
 // Preparer is an interface for preparing SQL statements.
 type Preparer interface {
 	Prepare(query string) (Stmt, error)
+	PrepareContext(ctx context.Context, query string) (Stmt, error)
 }
 
 // Tx is an interface for transaction operations.
@@ -32,14 +37,18 @@ type Tx interface {
 	Commit() error
 	Rollback() error
 	Exec(query string, args ...any) (Result, error)
+	ExecContext(ctx context.Context, query string, args ...any) (Result, error)
 }
 
 // Stmt wraps *sql.Stmt methods for executing prepared statements.
 type Stmt interface {
 	Close() error
 	QueryRow(args ...any) Row
+	QueryRowContext(ctx context.Context, args ...any) Row
 	Exec(args ...any) (Result, error)
+	ExecContext(ctx context.Context, args ...any) (Result, error)
 	Query(args ...any) (Rows, error)
+	QueryContext(ctx context.Context, args ...any) (Rows, error)
 }
 
 // Rows wraps *sql.Rows for scanning multiple results.
@@ -49,6 +58,8 @@ type Rows interface {
 	Scan(dest ...any) error
 	Close() error
 	Err() error
+	// Columns returns the column names of the rows.
+	Columns() ([]string, error)
 }
 
 // Row wraps *sql.Row for scanning a single result.
@@ -0,0 +1,8 @@
+package types
+
+// ExplainDialect renders the statement that explains a query for a
+// database.
+type ExplainDialect interface {
+	// Explain returns the statement that explains the query.
+	Explain(query string) string
+}
@@ -0,0 +1,7 @@
+package types
+
+// ParamMasker masks query parameter values before they are attached to
+// events or error context.
+type ParamMasker interface {
+	Mask(parameters []any) []any
+}
@@ -0,0 +1,7 @@
+package types
+
+// ReplicaSelector selects the replica connection to use for a read operation.
+type ReplicaSelector interface {
+	// Select returns one of the given replicas, or nil if none should be used.
+	Select(replicas []DB) DB
+}
@@ -0,0 +1,11 @@
+package types
+
+// SavepointDialect renders the savepoint statements for a database.
+type SavepointDialect interface {
+	// Savepoint returns the statement that creates the savepoint.
+	Savepoint(name string) string
+	// Release returns the statement that releases the savepoint.
+	Release(name string) string
+	// RollbackTo returns the statement that rolls back to the savepoint.
+	RollbackTo(name string) string
+}
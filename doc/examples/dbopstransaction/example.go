@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -82,22 +83,21 @@ func main() {
 //   - ctx: The context for the transaction.
 //   - db: The database connection.
 func RunSuccessfulTransaction(ctx context.Context, db types.DB) {
-	tx, err := BeginTx(ctx, db)
+	_, err := database.WithTx(
+		ctx, db, nil,
+		func(ctx context.Context, tx types.Tx) (any, error) {
+			// Insert an order and update it.
+			InsertOrder(ctx, tx)
+			UpdateOrder(ctx, tx, 1)
+
+			// Query the order count.
+			GetOrderCount(ctx, tx)
+			GetOrderByID(ctx, tx, 1)
+			return nil, nil
+		},
+	)
 	if err != nil {
-		log.Fatalf("Error beginning transaction: %v", err)
-	}
-
-	// Insert an order and update it.
-	InsertOrder(ctx, tx)
-	UpdateOrder(ctx, tx, 1)
-
-	// Query the order count.
-	GetOrderCount(ctx, tx)
-	GetOrderByID(ctx, tx, 1)
-
-	// Commit the transaction.
-	if err := tx.Commit(); err != nil {
-		log.Fatalf("Error committing transaction: %v", err)
+		log.Fatalf("Error running transaction: %v", err)
 	}
 
 	log.Println("Transaction committed successfully")
@@ -109,44 +109,29 @@ func RunSuccessfulTransaction(ctx context.Context, db types.DB) {
 //   - ctx: The context for the transaction.
 //   - db: The database connection.
 func RunRolledBackTransaction(ctx context.Context, db types.DB) {
-	tx, err := BeginTx(ctx, db)
-	if err != nil {
-		log.Fatalf("Error beginning transaction: %v", err)
-	}
-
-	// Insert an order and update it.
-	InsertOrder(ctx, tx)
-	UpdateOrder(ctx, tx, 1)
-
-	// Query the order count.
-	GetOrderCount(ctx, tx)
-	GetOrderByID(ctx, tx, 1)
-
-	// Rollback the transaction.
-	if err := tx.Rollback(); err != nil {
+	errRollback := errors.New("intentional rollback")
+	_, err := database.WithTx(
+		ctx, db, nil,
+		func(ctx context.Context, tx types.Tx) (any, error) {
+			// Insert an order and update it.
+			InsertOrder(ctx, tx)
+			UpdateOrder(ctx, tx, 1)
+
+			// Query the order count.
+			GetOrderCount(ctx, tx)
+			GetOrderByID(ctx, tx, 1)
+
+			// Returning an error rolls back the transaction.
+			return nil, errRollback
+		},
+	)
+	if !errors.Is(err, errRollback) {
 		log.Fatalf("Error rolling back transaction: %v", err)
 	}
 
 	log.Println("Transaction rolled back")
 }
 
-// Begin a transaction.
-//
-// Parameters:
-//   - db: The database connection.
-//
-// Returns:
-//   - types.Tx: The transaction object.
-//   - error: An error if the transaction fails.
-func BeginTx(ctx context.Context, db types.DB) (types.Tx, error) {
-	// Begin a transaction.
-	tx, err := db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	return tx, nil
-}
-
 // InsertOrder inserts a new order. It demonstrates how to
 // the Exec function can be used with transactions.
 //
@@ -30,7 +30,10 @@ func main() {
 	handler := server.NewHandler(emitterLogger)
 
 	// Create a HTTP server.
-	httpServer := server.DefaultHTTPServer(handler, port, endpoints)
+	httpServer, err := server.NewHTTPServer(handler, port, endpoints)
+	if err != nil {
+		panic(fmt.Errorf("server setup: %w", err))
+	}
 
 	// Start the server.
 	if err := server.StartServer(handler, httpServer, nil); err != nil {
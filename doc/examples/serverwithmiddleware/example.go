@@ -65,7 +65,10 @@ func main() {
 	handler := server.NewHandler(emitterLogger)
 
 	// Create a HTTP server.
-	httpServer := server.DefaultHTTPServer(handler, 8080, endpoints)
+	httpServer, err := server.NewHTTPServer(handler, 8080, endpoints)
+	if err != nil {
+		panic(err)
+	}
 
 	// Start the server.
 	if err := server.StartServer(handler, httpServer, nil); err != nil {
@@ -21,9 +21,16 @@ func (ds *dummyStack) Middlewares() types.Middlewares {
 func (ds *dummyStack) Clone() types.Stack {
 	return &dummyStack{id: ds.id + "_clone"}
 }
+func (ds *dummyStack) Get(id string) (types.Wrapper, bool) {
+	return nil, false
+}
+func (ds *dummyStack) Has(id string) bool { return false }
 func (ds *dummyStack) AddWrapper(w types.Wrapper) types.Stack {
 	return nil
 }
+func (ds *dummyStack) AddWrapperUnique(w types.Wrapper) (types.Stack, error) {
+	return nil, nil
+}
 func (ds *dummyStack) InsertBefore(
 	id string, w types.Wrapper,
 ) (types.Stack, bool) {
@@ -0,0 +1,101 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/pureapi/pureapi-core/util"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
+)
+
+// ErrorEnvelope is the body written by the output handlers for errors.
+type ErrorEnvelope struct {
+	Error   *util.DefaultAPIError `json:"error"`
+	Status  int                   `json:"status"`
+	TraceID string                `json:"trace_id,omitempty"`
+}
+
+// ErrorEnvelopeBuilder builds the error envelopes written by the output
+// handlers. Errors that are not APIErrors are written with the
+// ErrIDInternal ID only, so their messages are not exposed, unless internal
+// errors are exposed.
+type ErrorEnvelopeBuilder struct {
+	exposeInternal bool
+	traceIDFn      func(ctx context.Context) string
+}
+
+// NewErrorEnvelopeBuilder creates a new error envelope builder. Its trace
+// ID is the request ID set by the RequestID middleware.
+//
+// Returns:
+//   - *ErrorEnvelopeBuilder: A new ErrorEnvelopeBuilder instance.
+func NewErrorEnvelopeBuilder() *ErrorEnvelopeBuilder {
+	return &ErrorEnvelopeBuilder{
+		exposeInternal: false,
+		traceIDFn:      util.RequestIDFromContext,
+	}
+}
+
+// WithExposeInternal returns a new builder that writes the messages of
+// errors that are not APIErrors. It is meant for development only, since
+// the messages may contain internal details.
+//
+// Returns:
+//   - *ErrorEnvelopeBuilder: A new ErrorEnvelopeBuilder instance.
+func (b *ErrorEnvelopeBuilder) WithExposeInternal() *ErrorEnvelopeBuilder {
+	new := *b
+	new.exposeInternal = true
+	return &new
+}
+
+// WithTraceIDFn returns a new builder that reads the trace ID from the
+// request context with traceIDFn.
+//
+// Parameters:
+//   - traceIDFn: The function returning the trace ID, or "" for none.
+//
+// Returns:
+//   - *ErrorEnvelopeBuilder: A new ErrorEnvelopeBuilder instance.
+func (b *ErrorEnvelopeBuilder) WithTraceIDFn(
+	traceIDFn func(ctx context.Context) string,
+) *ErrorEnvelopeBuilder {
+	new := *b
+	new.traceIDFn = traceIDFn
+	return &new
+}
+
+// Build builds the error envelope for the error and status code.
+//
+// Parameters:
+//   - r: The HTTP request.
+//   - err: The error to write.
+//   - statusCode: The status code of the response.
+//
+// Returns:
+//   - *ErrorEnvelope: The error envelope.
+func (b *ErrorEnvelopeBuilder) Build(
+	r *http.Request, err error, statusCode int,
+) *ErrorEnvelope {
+	envelope := &ErrorEnvelope{
+		Error:  b.apiError(err),
+		Status: statusCode,
+	}
+	if b.traceIDFn != nil {
+		envelope.TraceID = b.traceIDFn(r.Context())
+	}
+	return envelope
+}
+
+// apiError converts an error to a DefaultAPIError for output.
+func (b *ErrorEnvelopeBuilder) apiError(err error) *util.DefaultAPIError {
+	var apiErr utiltypes.APIError
+	if errors.As(err, &apiErr) {
+		return util.APIErrorFrom(apiErr)
+	}
+	internal := util.NewAPIError(ErrIDInternal)
+	if b.exposeInternal {
+		return internal.WithMessage(err.Error())
+	}
+	return internal
+}
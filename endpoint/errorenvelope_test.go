@@ -0,0 +1,91 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/util"
+	"github.com/stretchr/testify/suite"
+)
+
+// ErrorEnvelopeBuilderTestSuite tests the error envelope builder.
+type ErrorEnvelopeBuilderTestSuite struct {
+	suite.Suite
+}
+
+// TestErrorEnvelopeBuilderTestSuite runs the test suite.
+func TestErrorEnvelopeBuilderTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorEnvelopeBuilderTestSuite))
+}
+
+// Test_Build_APIError tests that wrapped API errors are written with the
+// status code and the request ID as trace ID.
+func (s *ErrorEnvelopeBuilderTestSuite) Test_Build_APIError() {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(util.WithRequestID(req.Context(), "req-1"))
+	apiErr := util.NewAPIError("not_found").WithMessage("missing")
+
+	envelope := NewErrorEnvelopeBuilder().Build(
+		req, fmt.Errorf("lookup: %w", apiErr), http.StatusNotFound,
+	)
+	s.Equal("not_found", envelope.Error.ID())
+	s.Equal("missing", envelope.Error.Message())
+	s.Equal(http.StatusNotFound, envelope.Status)
+	s.Equal("req-1", envelope.TraceID)
+}
+
+// Test_Build_Opaque tests that plain errors hide their message unless
+// internal errors are exposed.
+func (s *ErrorEnvelopeBuilderTestSuite) Test_Build_Opaque() {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	plainErr := errors.New("connection refused")
+
+	envelope := NewErrorEnvelopeBuilder().Build(
+		req, plainErr, http.StatusInternalServerError,
+	)
+	s.Equal(ErrIDInternal, envelope.Error.ID())
+	s.Empty(envelope.Error.Message())
+	s.Empty(envelope.TraceID)
+
+	envelope = NewErrorEnvelopeBuilder().WithExposeInternal().Build(
+		req, plainErr, http.StatusInternalServerError,
+	)
+	s.Equal(ErrIDInternal, envelope.Error.ID())
+	s.Equal("connection refused", envelope.Error.Message())
+}
+
+// Test_Build_TraceIDFn tests that a custom trace ID function is used.
+func (s *ErrorEnvelopeBuilderTestSuite) Test_Build_TraceIDFn() {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	builder := NewErrorEnvelopeBuilder().WithTraceIDFn(
+		func(ctx context.Context) string { return "trace-1" },
+	)
+	envelope := builder.Build(req, errors.New("x"), http.StatusBadGateway)
+	s.Equal("trace-1", envelope.TraceID)
+	s.Equal(http.StatusBadGateway, envelope.Status)
+}
+
+// Test_JSONOutputHandler tests that the JSON output handler writes the
+// envelope of the configured builder.
+func (s *ErrorEnvelopeBuilderTestSuite) Test_JSONOutputHandler() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{
+		ErrorEnvelope: NewErrorEnvelopeBuilder().WithTraceIDFn(
+			func(ctx context.Context) string { return "trace-1" },
+		),
+	})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := handler.Handle(
+		rec, req, nil, errors.New("secret"), http.StatusInternalServerError,
+	)
+	s.Require().NoError(err)
+	s.JSONEq(
+		`{"error":{"id":"internal_error"},"status":500,"trace_id":"trace-1"}`,
+		rec.Body.String(),
+	)
+}
@@ -0,0 +1,249 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	endpointtypes "github.com/pureapi/pureapi-core/endpoint/types"
+	"github.com/pureapi/pureapi-core/util"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
+)
+
+// Error IDs used by the default handlers.
+const (
+	// ErrIDInternal is used for errors that have no mapping.
+	ErrIDInternal = "internal_error"
+
+	// ErrIDInvalidInput is used for input that can not be decoded.
+	ErrIDInvalidInput = "invalid_input"
+
+	// ErrIDRequestTooLarge is used for request bodies over the size limit.
+	ErrIDRequestTooLarge = "request_too_large"
+
+	// ErrIDValidation is used for input that fails validation.
+	ErrIDValidation = "validation_error"
+)
+
+// Define events.
+const (
+	// EventUnexpectedError event is emitted when the error handler receives
+	// an error that has no status mapping.
+	EventUnexpectedError utiltypes.EventType = "event_unexpected_error"
+
+	// EventInvalidInput event is emitted when the error handler receives an
+	// input decode error. The event data holds the raw decoder error.
+	EventInvalidInput utiltypes.EventType = "event_invalid_input"
+)
+
+// errorStatus maps a target error to a status code and an error ID.
+type errorStatus struct {
+	target error
+	status int
+	id     string
+}
+
+// ErrorRegistry maps error IDs and errors to HTTP status codes.
+type ErrorRegistry struct {
+	ids    map[string]int
+	errors []errorStatus
+}
+
+// NewErrorRegistry creates a new empty error registry.
+//
+// Returns:
+//   - *ErrorRegistry: A new ErrorRegistry instance.
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{
+		ids:    map[string]int{},
+		errors: []errorStatus{},
+	}
+}
+
+// WithID maps an APIError ID to a status code.
+//
+// Parameters:
+//   - id: The APIError ID.
+//   - status: The HTTP status code.
+//
+// Returns:
+//   - *ErrorRegistry: A new ErrorRegistry instance.
+func (r *ErrorRegistry) WithID(id string, status int) *ErrorRegistry {
+	new := *r
+	new.ids = make(map[string]int, len(r.ids)+1)
+	for k, v := range r.ids {
+		new.ids[k] = v
+	}
+	new.ids[id] = status
+	return &new
+}
+
+// WithError maps a target error to a status code. Errors matching the
+// target with errors.Is are returned to the client as an APIError with the
+// given ID.
+//
+// Parameters:
+//   - target: The target error, such as a sentinel error.
+//   - status: The HTTP status code.
+//   - id: The ID of the APIError returned to the client.
+//
+// Returns:
+//   - *ErrorRegistry: A new ErrorRegistry instance.
+func (r *ErrorRegistry) WithError(
+	target error, status int, id string,
+) *ErrorRegistry {
+	new := *r
+	new.errors = append(
+		append([]errorStatus{}, r.errors...),
+		errorStatus{target: target, status: status, id: id},
+	)
+	return &new
+}
+
+// defaultErrorHandler maps errors to status codes using a registry.
+type defaultErrorHandler struct {
+	registry      *ErrorRegistry
+	emitterLogger utiltypes.EmitterLogger
+}
+
+// defaultErrorHandler implements the ErrorHandler interface.
+var _ endpointtypes.ErrorHandler = (*defaultErrorHandler)(nil)
+
+// NewDefaultErrorHandler creates a new default error handler.
+//
+// Parameters:
+//   - registry: The error registry. If nil, an empty registry is used.
+//
+// Returns:
+//   - *defaultErrorHandler: A new defaultErrorHandler instance.
+func NewDefaultErrorHandler(registry *ErrorRegistry) *defaultErrorHandler {
+	if registry == nil {
+		registry = NewErrorRegistry()
+	}
+	return &defaultErrorHandler{
+		registry:      registry,
+		emitterLogger: defaultEmitterLogger(),
+	}
+}
+
+// WithEmitterLogger adds an emitter logger to the error handler.
+//
+// Parameters:
+//   - emitterLogger: The emitter logger.
+//
+// Returns:
+//   - *defaultErrorHandler: A new defaultErrorHandler instance.
+func (h *defaultErrorHandler) WithEmitterLogger(
+	emitterLogger utiltypes.EmitterLogger,
+) *defaultErrorHandler {
+	new := *h
+	if emitterLogger == nil {
+		new.emitterLogger = defaultEmitterLogger()
+	} else {
+		new.emitterLogger = emitterLogger
+	}
+	return &new
+}
+
+// Handle maps the error to a status code and an APIError. Errors are
+// unwrapped with errors.As and errors.Is. APIErrors with a status set by
+// WithStatus use that status, and APIErrors with a registered ID are
+// returned as is. Registered errors are returned with their registered ID.
+// A *util.ValidationError is mapped to 422 with the field errors as data, and
+// *DecodeError to 400, or 413 for oversized bodies. The message of a
+// *DecodeError names only the invalid JSON field or param, and the raw error
+// is logged at debug level. Other errors are logged and mapped to 500.
+//
+// Parameters:
+//   - err: The error to map.
+//
+// Returns:
+//   - int: The HTTP status code.
+//   - utiltypes.APIError: The APIError to write to the client.
+func (h *defaultErrorHandler) Handle(err error) (int, utiltypes.APIError) {
+	var defaultErr *util.DefaultAPIError
+	if errors.As(err, &defaultErr) && defaultErr.ErrStatus != 0 {
+		return defaultErr.ErrStatus, defaultErr
+	}
+	var apiErr utiltypes.APIError
+	isAPIErr := errors.As(err, &apiErr)
+	if isAPIErr {
+		if status, ok := h.registry.ids[apiErr.ID()]; ok {
+			return status, apiErr
+		}
+	}
+	for _, mapping := range h.registry.errors {
+		if errors.Is(err, mapping.target) {
+			return mapping.status, util.NewAPIError(mapping.id)
+		}
+	}
+	var validationErr *util.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusUnprocessableEntity,
+			util.NewAPIError(ErrIDValidation).WithData(validationErr.Fields)
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge,
+			util.NewAPIError(ErrIDRequestTooLarge)
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		h.emitterLogger.Debug(
+			utiltypes.NewEvent(
+				EventInvalidInput,
+				fmt.Sprintf("Invalid input: %v", decodeErr.Err),
+			).WithData(map[string]any{"err": decodeErr.Err}),
+		)
+		return http.StatusBadRequest, util.NewAPIError(ErrIDInvalidInput).
+			WithMessage(decodeErrorMessage(decodeErr.Err))
+	}
+	h.emitterLogger.Error(
+		utiltypes.NewEvent(
+			EventUnexpectedError,
+			fmt.Sprintf("Unexpected error: %v", err),
+		).WithData(map[string]any{"err": err}),
+	)
+	if isAPIErr {
+		return http.StatusInternalServerError, apiErr
+	}
+	return http.StatusInternalServerError, util.NewAPIError(ErrIDInternal)
+}
+
+// decodeErrorMessage returns a client safe message for a decode error. The
+// message names only the JSON field or param, never the Go types.
+func decodeErrorMessage(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return "invalid value"
+		}
+		return fmt.Sprintf("invalid value for field %q", typeErr.Field)
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return "malformed JSON"
+	}
+	if errors.Is(err, errTrailingData) {
+		return errTrailingData.Error()
+	}
+	var paramErr *paramError
+	if errors.As(err, &paramErr) {
+		return fmt.Sprintf(
+			"invalid value for %s param %q", paramErr.kind, paramErr.name,
+		)
+	}
+	// The decoder has no typed error for unknown fields.
+	if field, ok := strings.CutPrefix(
+		err.Error(), "json: unknown field ",
+	); ok {
+		return "unknown field " + field
+	}
+	return "invalid input"
+}
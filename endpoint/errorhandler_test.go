@@ -0,0 +1,183 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/util"
+	"github.com/stretchr/testify/suite"
+)
+
+// errTestNotFound is a sentinel error used in the error handler tests.
+var errTestNotFound = errors.New("not found")
+
+// ErrorHandlerTestSuite tests the default error handler.
+type ErrorHandlerTestSuite struct {
+	suite.Suite
+	registry *ErrorRegistry
+}
+
+// TestErrorHandlerTestSuite runs the test suite.
+func TestErrorHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorHandlerTestSuite))
+}
+
+// SetupTest creates the registry used by the tests.
+func (s *ErrorHandlerTestSuite) SetupTest() {
+	s.registry = NewErrorRegistry().
+		WithID("INVALID_PREDICATE", http.StatusBadRequest).
+		WithError(errTestNotFound, http.StatusNotFound, "not_found")
+}
+
+// Test_Handle_KnownID tests that registered API error IDs are mapped.
+func (s *ErrorHandlerTestSuite) Test_Handle_KnownID() {
+	handler := NewDefaultErrorHandler(s.registry)
+	apiErr := util.NewAPIError("INVALID_PREDICATE")
+
+	status, out := handler.Handle(apiErr)
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal(apiErr, out)
+}
+
+// Test_Handle_Wrapped tests that wrapped errors are unwrapped.
+func (s *ErrorHandlerTestSuite) Test_Handle_Wrapped() {
+	handler := NewDefaultErrorHandler(s.registry)
+
+	status, out := handler.Handle(
+		fmt.Errorf("wrap: %w", util.NewAPIError("INVALID_PREDICATE")),
+	)
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal("INVALID_PREDICATE", out.ID())
+
+	status, out = handler.Handle(fmt.Errorf("query: %w", errTestNotFound))
+	s.Equal(http.StatusNotFound, status)
+	s.Equal("not_found", out.ID())
+}
+
+// Test_Handle_Status tests that a status set on the API error is used.
+func (s *ErrorHandlerTestSuite) Test_Handle_Status() {
+	handler := NewDefaultErrorHandler(s.registry)
+	apiErr := util.NewAPIError("INVALID_PREDICATE").
+		WithStatus(http.StatusUnprocessableEntity)
+
+	status, out := handler.Handle(fmt.Errorf("wrap: %w", apiErr))
+	s.Equal(http.StatusUnprocessableEntity, status)
+	s.Equal(apiErr, out)
+}
+
+// Test_Handle_ValidationError tests that validation errors are mapped to 422
+// with the field errors as data.
+func (s *ErrorHandlerTestSuite) Test_Handle_ValidationError() {
+	handler := NewDefaultErrorHandler(nil)
+	validationErr := util.NewValidationError().
+		AddField("name", "required", "").
+		AddField("age", "min", "")
+
+	status, out := handler.Handle(fmt.Errorf("validate: %w", validationErr))
+	s.Equal(http.StatusUnprocessableEntity, status)
+	s.Equal(ErrIDValidation, out.ID())
+	s.Equal(validationErr.Fields, out.Data())
+}
+
+// Test_Handle_DecodeError tests that decode errors are mapped to 400 and
+// oversized bodies to 413.
+func (s *ErrorHandlerTestSuite) Test_Handle_DecodeError() {
+	handler := NewDefaultErrorHandler(nil)
+
+	status, out := handler.Handle(&DecodeError{Err: errors.New("bad json")})
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal(ErrIDInvalidInput, out.ID())
+	s.Equal("invalid input", out.Message())
+
+	status, out = handler.Handle(
+		&DecodeError{Err: &http.MaxBytesError{Limit: 10}},
+	)
+	s.Equal(http.StatusRequestEntityTooLarge, status)
+	s.Equal(ErrIDRequestTooLarge, out.ID())
+}
+
+// Test_Handle_DecodeErrorMessage tests that decode error messages name only
+// the JSON field and that the raw error is logged.
+func (s *ErrorHandlerTestSuite) Test_Handle_DecodeErrorMessage() {
+	type body struct {
+		Age int `json:"age"`
+	}
+	decode := func(raw string) error {
+		decoder := json.NewDecoder(strings.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		return &DecodeError{Err: decoder.Decode(&body{})}
+	}
+	tests := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{"type", decode(`{"age":"x"}`), `invalid value for field "age"`},
+		{"syntax", decode(`{"age":}`), "malformed JSON at offset 8"},
+		{"unknown field", decode(`{"name":"x"}`), `unknown field "name"`},
+		{"truncated", decode(`{"age":1`), "malformed JSON"},
+		{
+			"param",
+			&DecodeError{Err: &paramError{
+				kind: "query", name: "page", err: strconv.ErrSyntax,
+			}},
+			`invalid value for query param "page"`,
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			emitter := &dummyEmitterLogger{}
+			handler := NewDefaultErrorHandler(nil).WithEmitterLogger(emitter)
+
+			status, out := handler.Handle(test.err)
+			s.Equal(http.StatusBadRequest, status)
+			s.Equal(test.message, out.Message())
+			s.NotContains(out.Message(), "Go")
+			s.NotContains(out.Message(), "struct")
+
+			s.Require().Len(emitter.events, 1)
+			s.Equal(EventInvalidInput, emitter.events[0].Type)
+			s.Equal(
+				map[string]any{"err": errors.Unwrap(test.err)},
+				emitter.events[0].Data,
+			)
+		})
+	}
+}
+
+// Test_Handle_Unknown tests that unknown errors are logged and mapped to
+// 500.
+func (s *ErrorHandlerTestSuite) Test_Handle_Unknown() {
+	emitter := &dummyEmitterLogger{}
+	handler := NewDefaultErrorHandler(s.registry).WithEmitterLogger(emitter)
+
+	status, out := handler.Handle(errors.New("boom"))
+	s.Equal(http.StatusInternalServerError, status)
+	s.Equal(ErrIDInternal, out.ID())
+	s.Empty(out.Message())
+
+	apiErr := util.NewAPIError("UNKNOWN")
+	status, out = handler.Handle(apiErr)
+	s.Equal(http.StatusInternalServerError, status)
+	s.Equal(apiErr, out)
+
+	s.Require().Len(emitter.events, 2)
+	s.Equal(EventUnexpectedError, emitter.events[0].Type)
+}
+
+// Test_ErrorRegistry_Copy tests that the registry builders return copies.
+func (s *ErrorHandlerTestSuite) Test_ErrorRegistry_Copy() {
+	base := NewErrorRegistry()
+	extended := base.WithID("A", http.StatusConflict).
+		WithError(errTestNotFound, http.StatusNotFound, "not_found")
+
+	s.Empty(base.ids)
+	s.Empty(base.errors)
+	s.Len(extended.ids, 1)
+	s.Len(extended.errors, 1)
+}
@@ -1,6 +1,7 @@
 package endpoint
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -24,10 +25,17 @@ type HandlerLogicFn[Input any] func(
 	w http.ResponseWriter, r *http.Request, i *Input,
 ) (any, error)
 
+// ValidateFn is a function for validating the endpoint input before the
+// handler logic runs. To report per-field problems, return a
+// util.ValidationError holding all field errors so the error handler can pass
+// them to the client.
+type ValidateFn[Input any] func(ctx context.Context, i *Input) error
+
 // defaultHandler represents an endpoint with input, business logic, and
 // output.
 type defaultHandler[Input any] struct {
 	inputHandler   endpointtypes.InputHandler[Input]
+	validateFn     ValidateFn[Input]
 	handlerLogicFn HandlerLogicFn[Input]
 	errorHandler   endpointtypes.ErrorHandler
 	outputHandler  endpointtypes.OutputHandler
@@ -55,6 +63,7 @@ func NewHandler[Input any](
 ) *defaultHandler[Input] {
 	return &defaultHandler[Input]{
 		inputHandler:   inputHandler,
+		validateFn:     nil,
 		handlerLogicFn: handlerLogicFn,
 		errorHandler:   errorHandler,
 		outputHandler:  outputHandler,
@@ -81,8 +90,26 @@ func (h *defaultHandler[Input]) WithEmitterLogger(
 	return &new
 }
 
-// Handle executes common endpoints logic. It calls the input handler, handler
-// logic, and output handler.
+// WithValidateFn adds an input validation function to the handler. It is
+// called after the input handler and before the handler logic. If it returns
+// an error, the handler logic is skipped and the error is passed unchanged to
+// the error handler.
+//
+// Parameters:
+//   - validateFn: The validation function.
+//
+// Returns:
+//   - *handler: A new handler instance.
+func (h *defaultHandler[Input]) WithValidateFn(
+	validateFn ValidateFn[Input],
+) *defaultHandler[Input] {
+	new := *h
+	new.validateFn = validateFn
+	return &new
+}
+
+// Handle executes common endpoints logic. It calls the input handler, the
+// optional validation function, handler logic, and output handler.
 //
 // Parameters:
 //   - w: The HTTP response writer.
@@ -96,6 +123,13 @@ func (h *defaultHandler[Input]) Handle(
 		h.handleError(w, r, err)
 		return
 	}
+	// Validate input.
+	if h.validateFn != nil {
+		if err := h.validateFn(r.Context(), input); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+	}
 	// Call handler logic.
 	out, err := h.handlerLogicFn(w, r, input)
 	if err != nil {
@@ -1,6 +1,7 @@
 package endpoint
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -260,3 +261,62 @@ func (s *HandlerTestSuite) Test_Handle_NilEmitterLogger() {
 	s.True(outHandler.called, "Output handler should be called")
 	s.Equal("logic", rr.Body.String(), "Expected output 'logic'")
 }
+
+// Test_Handle_ValidateFn verifies that the validation function runs after the
+// input handler and before the handler logic, and that its error reaches the
+// error handler unchanged.
+func (s *HandlerTestSuite) Test_Handle_ValidateFn() {
+	inputVal := "input"
+	validationErr := errors.New("validation error")
+	testCases := []struct {
+		name          string
+		validateErr   error
+		expectedCalls []string
+		expectedErr   error
+	}{
+		{
+			name:          "Valid",
+			validateErr:   nil,
+			expectedCalls: []string{"validate:input", "logic"},
+		},
+		{
+			name:          "Invalid",
+			validateErr:   validationErr,
+			expectedCalls: []string{"validate:input"},
+			expectedErr:   validationErr,
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			var calls []string
+			validateFn := func(ctx context.Context, i *string) error {
+				calls = append(calls, "validate:"+*i)
+				return tc.validateErr
+			}
+			logicFn := func(
+				w http.ResponseWriter, r *http.Request, i *string,
+			) (any, error) {
+				calls = append(calls, "logic")
+				return "logic", nil
+			}
+			errHandler := &dummyErrorHandler{retStatus: 422}
+			handler := NewHandler(
+				&dummyInputHandler{result: &inputVal},
+				logicFn,
+				errHandler,
+				&dummyOutputHandler{},
+			).WithValidateFn(validateFn)
+
+			rr := httptest.NewRecorder()
+			handler.Handle(rr, httptest.NewRequest("POST", "/validate", nil))
+
+			s.Equal(tc.expectedCalls, calls)
+			if tc.expectedErr == nil {
+				s.Nil(errHandler.capturedErr)
+			} else {
+				s.Same(tc.expectedErr, errHandler.capturedErr)
+				s.Equal(422, rr.Result().StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	endpointtypes "github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// DefaultInputMaxBodySize is the request body limit used by the JSON input
+// handler when no limit is configured.
+const DefaultInputMaxBodySize int64 = 1 << 20
+
+// errTrailingData is returned when the request body has data after the JSON
+// value.
+var errTrailingData = errors.New("unexpected data after JSON body")
+
+// DecodeError is returned by the JSON input handler when the request input
+// can not be decoded. The default error handler maps it to 400.
+type DecodeError struct {
+	Err error
+}
+
+// Error returns the error message.
+//
+// Returns:
+//   - string: The error message.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode input: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+//
+// Returns:
+//   - error: The underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// JSONInputOptions configures the JSON input handler.
+type JSONInputOptions struct {
+	// DisallowUnknownFields rejects bodies with fields not in the input.
+	DisallowUnknownFields bool
+	// MaxBodySize is the body limit in bytes. Zero uses
+	// DefaultInputMaxBodySize and a negative value disables the limit.
+	MaxBodySize int64
+	// MergeParams sets input fields tagged with `query:"name"` or
+	// `path:"name"` from the query string and the path values. Params are
+	// applied after the body, so they take precedence.
+	MergeParams bool
+}
+
+// defaultJSONInputHandler decodes request bodies as JSON.
+type defaultJSONInputHandler[Input any] struct {
+	disallowUnknownFields bool
+	maxBodySize           int64
+	mergeParams           bool
+}
+
+// defaultJSONInputHandler implements the InputHandler interface.
+var _ endpointtypes.InputHandler[any] = (*defaultJSONInputHandler[any])(nil)
+
+// NewJSONInputHandler creates a new JSON input handler.
+//
+// Parameters:
+//   - opts: The JSON input options.
+//
+// Returns:
+//   - *defaultJSONInputHandler: A new defaultJSONInputHandler instance.
+func NewJSONInputHandler[Input any](
+	opts JSONInputOptions,
+) *defaultJSONInputHandler[Input] {
+	maxBodySize := opts.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = DefaultInputMaxBodySize
+	}
+	return &defaultJSONInputHandler[Input]{
+		disallowUnknownFields: opts.DisallowUnknownFields,
+		maxBodySize:           maxBodySize,
+		mergeParams:           opts.MergeParams,
+	}
+}
+
+// Handle decodes the request body into a new input. An empty body leaves
+// the input at its zero value. Decoding failures, including bodies over the
+// size limit, are returned as *DecodeError.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The decoded input.
+//   - error: A *DecodeError if the input can not be decoded.
+func (h *defaultJSONInputHandler[Input]) Handle(
+	w http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	input := new(Input)
+	if r.Body != nil && r.Body != http.NoBody {
+		if err := h.decodeBody(w, r, input); err != nil {
+			return nil, &DecodeError{Err: err}
+		}
+	}
+	if h.mergeParams {
+		if err := mergeParams(r, input); err != nil {
+			return nil, &DecodeError{Err: err}
+		}
+	}
+	return input, nil
+}
+
+// decodeBody decodes the request body into the input.
+func (h *defaultJSONInputHandler[Input]) decodeBody(
+	w http.ResponseWriter, r *http.Request, input *Input,
+) error {
+	body := r.Body
+	if h.maxBodySize > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	}
+	decoder := json.NewDecoder(body)
+	if h.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(input); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return err
+		}
+		return errTrailingData
+	}
+	return nil
+}
+
+// paramError is returned when a query or path param can not be parsed.
+type paramError struct {
+	kind string
+	name string
+	err  error
+}
+
+// Error returns the error message.
+func (e *paramError) Error() string {
+	return fmt.Sprintf("%s param %q: %v", e.kind, e.name, e.err)
+}
+
+// Unwrap returns the underlying error.
+func (e *paramError) Unwrap() error {
+	return e.err
+}
+
+// mergeParams sets the input fields tagged with "query" or "path" from the
+// request.
+func mergeParams(r *http.Request, input any) error {
+	value := reflect.ValueOf(input).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	query := r.URL.Query()
+	valueType := value.Type()
+	for i := range valueType.NumField() {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if name, ok := field.Tag.Lookup("query"); ok && query.Has(name) {
+			err := setParam(value.Field(i), query.Get(name))
+			if err != nil {
+				return &paramError{kind: "query", name: name, err: err}
+			}
+		}
+		if name, ok := field.Tag.Lookup("path"); ok {
+			raw := r.PathValue(name)
+			if raw == "" {
+				continue
+			}
+			if err := setParam(value.Field(i), raw); err != nil {
+				return &paramError{kind: "path", name: name, err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// setParam parses the raw param into the field based on its kind.
+func setParam(field reflect.Value, raw string) error {
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setParam(ptr.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		v, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		v, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// testJSONInput is the input decoded in the JSON input handler tests.
+type testJSONInput struct {
+	Name  string `json:"name"`
+	ID    int    `json:"id" path:"id"`
+	Limit *int   `json:"limit" query:"limit"`
+}
+
+// JSONInputHandlerTestSuite tests the JSON input handler.
+type JSONInputHandlerTestSuite struct {
+	suite.Suite
+}
+
+// TestJSONInputHandlerTestSuite runs the test suite.
+func TestJSONInputHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(JSONInputHandlerTestSuite))
+}
+
+// Test_Handle_Success tests that a valid body is decoded.
+func (s *JSONInputHandlerTestSuite) Test_Handle_Success() {
+	handler := NewJSONInputHandler[testJSONInput](JSONInputOptions{})
+	req := httptest.NewRequest(
+		http.MethodPost, "/", strings.NewReader(`{"name":"test","id":1}`),
+	)
+
+	input, err := handler.Handle(httptest.NewRecorder(), req)
+	s.Require().NoError(err)
+	s.Equal("test", input.Name)
+	s.Equal(1, input.ID)
+}
+
+// Test_Handle_EmptyBody tests that an empty body yields a zero input.
+func (s *JSONInputHandlerTestSuite) Test_Handle_EmptyBody() {
+	handler := NewJSONInputHandler[testJSONInput](JSONInputOptions{})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	input, err := handler.Handle(httptest.NewRecorder(), req)
+	s.Require().NoError(err)
+	s.Equal(testJSONInput{}, *input)
+}
+
+// Test_Handle_MalformedJSON tests that malformed JSON returns a DecodeError.
+func (s *JSONInputHandlerTestSuite) Test_Handle_MalformedJSON() {
+	handler := NewJSONInputHandler[testJSONInput](JSONInputOptions{})
+	for _, body := range []string{`{"name":`, `{"name":"a"} {}`} {
+		req := httptest.NewRequest(
+			http.MethodPost, "/", strings.NewReader(body),
+		)
+		input, err := handler.Handle(httptest.NewRecorder(), req)
+		s.Nil(input, body)
+		var decodeErr *DecodeError
+		s.ErrorAs(err, &decodeErr, body)
+	}
+}
+
+// Test_Handle_UnknownFields tests that unknown fields are rejected only when
+// configured.
+func (s *JSONInputHandlerTestSuite) Test_Handle_UnknownFields() {
+	body := `{"name":"test","extra":true}`
+
+	lenient := NewJSONInputHandler[testJSONInput](JSONInputOptions{})
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	_, err := lenient.Handle(httptest.NewRecorder(), req)
+	s.Require().NoError(err)
+
+	strict := NewJSONInputHandler[testJSONInput](JSONInputOptions{
+		DisallowUnknownFields: true,
+	})
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	_, err = strict.Handle(httptest.NewRecorder(), req)
+	var decodeErr *DecodeError
+	s.Require().ErrorAs(err, &decodeErr)
+	s.Contains(err.Error(), "extra")
+}
+
+// Test_Handle_OversizedBody tests that bodies over the limit are rejected.
+func (s *JSONInputHandlerTestSuite) Test_Handle_OversizedBody() {
+	handler := NewJSONInputHandler[testJSONInput](JSONInputOptions{
+		MaxBodySize: 10,
+	})
+	req := httptest.NewRequest(
+		http.MethodPost, "/", strings.NewReader(`{"name":"too long"}`),
+	)
+
+	_, err := handler.Handle(httptest.NewRecorder(), req)
+	var decodeErr *DecodeError
+	s.Require().ErrorAs(err, &decodeErr)
+	var maxBytesErr *http.MaxBytesError
+	s.ErrorAs(err, &maxBytesErr)
+}
+
+// Test_Handle_MergeParams tests that query and path params are merged into
+// the input.
+func (s *JSONInputHandlerTestSuite) Test_Handle_MergeParams() {
+	handler := NewJSONInputHandler[testJSONInput](JSONInputOptions{
+		MergeParams: true,
+	})
+	req := httptest.NewRequest(
+		http.MethodPost, "/items/7?limit=5", strings.NewReader(`{"id":1}`),
+	)
+	req.SetPathValue("id", "7")
+
+	input, err := handler.Handle(httptest.NewRecorder(), req)
+	s.Require().NoError(err)
+	s.Equal(7, input.ID)
+	s.Require().NotNil(input.Limit)
+	s.Equal(5, *input.Limit)
+}
+
+// Test_Handle_InvalidParam tests that unparsable params return a
+// DecodeError.
+func (s *JSONInputHandlerTestSuite) Test_Handle_InvalidParam() {
+	handler := NewJSONInputHandler[testJSONInput](JSONInputOptions{
+		MergeParams: true,
+	})
+	req := httptest.NewRequest(http.MethodGet, "/?limit=abc", nil)
+
+	_, err := handler.Handle(httptest.NewRecorder(), req)
+	var decodeErr *DecodeError
+	s.Require().ErrorAs(err, &decodeErr)
+	s.Contains(err.Error(), "limit")
+}
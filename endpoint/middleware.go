@@ -39,7 +39,10 @@ func (m defaultMiddlewares) Middlewares() []types.Middleware {
 // Chain applies a sequence of middlewares to an http.Handler. During a request
 // the middlewaress are applied in the order they are provided.
 // The middlewares are applied so that the first middleware in the list becomes
-// the outermost wrapper.
+// the outermost wrapper. It runs first before the handler and last after it,
+// which suits e.g. logging, while the last middleware sits next to the
+// handler, which suits e.g. body limits. Use Reverse to make the last
+// middleware the outermost instead.
 //
 // Example with middlewares m1, m2
 //
@@ -70,3 +73,21 @@ func (m defaultMiddlewares) Add(
 	allMiddlewares = append(allMiddlewares, middlewares...)
 	return NewMiddlewares(allMiddlewares...)
 }
+
+// Reverse returns a new Middlewares instance with the middlewares in reverse
+// order, so that Chain makes the last added middleware the outermost
+// wrapper.
+//
+// Example with middlewares m1, m2
+//
+//	Reverse().Chain(finalHandler) yields m2(m1(finalHandler)).
+//
+// Returns:
+//   - types.Middlewares: A new Middlewares instance.
+func (m defaultMiddlewares) Reverse() types.Middlewares {
+	reversed := make([]types.Middleware, len(m.middlewares))
+	for i, middleware := range m.middlewares {
+		reversed[len(m.middlewares)-1-i] = middleware
+	}
+	return NewMiddlewares(reversed...)
+}
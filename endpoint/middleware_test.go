@@ -129,3 +129,62 @@ func TestAddx(t *testing.T) {
 	assert.Equal(t, expected, events,
 		"Expected chain to be %v, but got %v", expected, events)
 }
+
+// TestReverse tests that Reverse makes the last middleware the outermost
+// wrapper and leaves the original instance unchanged.
+func TestReverse(t *testing.T) {
+	var events []string
+	var mws types.Middlewares = NewMiddlewares(
+		makeMiddleware("m1", &events),
+		makeMiddleware("m2", &events),
+		makeMiddleware("m3", &events),
+	)
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events = append(events, "final")
+	})
+
+	mws.Reverse().Chain(final).ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil),
+	)
+	assert.Equal(t, []string{
+		"m3-pre", "m2-pre", "m1-pre", "final",
+		"m1-post", "m2-post", "m3-post",
+	}, events)
+
+	events = nil
+	mws.Chain(final).ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil),
+	)
+	assert.Equal(t, []string{
+		"m1-pre", "m2-pre", "m3-pre", "final",
+		"m3-post", "m2-post", "m1-post",
+	}, events)
+}
+
+// TestReverse_Stack tests that Reverse is available through the Middlewares
+// returned by a Stack and that reversing twice restores the order.
+func TestReverse_Stack(t *testing.T) {
+	var events []string
+	stack := NewStack(
+		NewWrapper("m1", makeMiddleware("m1", &events)),
+		NewWrapper("m2", makeMiddleware("m2", &events)),
+	)
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events = append(events, "final")
+	})
+
+	stack.Middlewares().Reverse().Chain(final).ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil),
+	)
+	assert.Equal(t, []string{
+		"m2-pre", "m1-pre", "final", "m1-post", "m2-post",
+	}, events)
+
+	events = nil
+	stack.Middlewares().Reverse().Reverse().Chain(final).ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil),
+	)
+	assert.Equal(t, []string{
+		"m1-pre", "m2-pre", "final", "m2-post", "m1-post",
+	}, events)
+}
@@ -0,0 +1,205 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	endpointtypes "github.com/pureapi/pureapi-core/endpoint/types"
+	"github.com/pureapi/pureapi-core/util"
+)
+
+// MediaTypeJSON is the media type of the default encoder.
+const MediaTypeJSON = "application/json"
+
+// ErrIDNotAcceptable is the API error ID written when no registered encoder
+// matches the Accept header.
+const ErrIDNotAcceptable = "not_acceptable"
+
+// defaultJSONEncoder encodes output as JSON.
+type defaultJSONEncoder struct{}
+
+// defaultJSONEncoder implements the Encoder interface.
+var _ endpointtypes.Encoder = (*defaultJSONEncoder)(nil)
+
+// NewJSONEncoder creates a new JSON encoder.
+//
+// Returns:
+//   - *defaultJSONEncoder: A new defaultJSONEncoder instance.
+func NewJSONEncoder() *defaultJSONEncoder {
+	return &defaultJSONEncoder{}
+}
+
+// Encode encodes v as JSON followed by a newline.
+//
+// Parameters:
+//   - v: The value to encode.
+//
+// Returns:
+//   - []byte: The encoded value.
+//   - error: An error if encoding fails.
+func (e *defaultJSONEncoder) Encode(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// defaultNegotiatingOutputHandler writes endpoint responses with the
+// encoder registered for the best media type in the Accept header. JSON is
+// registered by default and used when the request has no Accept header.
+type defaultNegotiatingOutputHandler struct {
+	encoders      map[string]endpointtypes.Encoder
+	mediaTypes    []string
+	errorEnvelope *ErrorEnvelopeBuilder
+}
+
+// defaultNegotiatingOutputHandler implements the OutputHandler interface.
+var _ endpointtypes.OutputHandler = (*defaultNegotiatingOutputHandler)(nil)
+
+// NewContentNegotiatingOutputHandler creates a new content negotiating
+// output handler with the JSON encoder registered.
+//
+// Returns:
+//   - *defaultNegotiatingOutputHandler: A new instance.
+func NewContentNegotiatingOutputHandler() *defaultNegotiatingOutputHandler {
+	return (&defaultNegotiatingOutputHandler{
+		encoders:      map[string]endpointtypes.Encoder{},
+		mediaTypes:    []string{},
+		errorEnvelope: NewErrorEnvelopeBuilder(),
+	}).RegisterEncoder(MediaTypeJSON, NewJSONEncoder())
+}
+
+// WithErrorEnvelope returns a new handler that builds error bodies with the
+// given envelope builder. If nil, NewErrorEnvelopeBuilder is used.
+//
+// Parameters:
+//   - builder: The error envelope builder.
+//
+// Returns:
+//   - *defaultNegotiatingOutputHandler: A new handler.
+func (h *defaultNegotiatingOutputHandler) WithErrorEnvelope(
+	builder *ErrorEnvelopeBuilder,
+) *defaultNegotiatingOutputHandler {
+	if builder == nil {
+		builder = NewErrorEnvelopeBuilder()
+	}
+	new := *h
+	new.encoders = maps.Clone(h.encoders)
+	new.mediaTypes = slices.Clone(h.mediaTypes)
+	new.errorEnvelope = builder
+	return &new
+}
+
+// RegisterEncoder registers the encoder for the media type, replacing any
+// encoder already registered for it. Encoders must be registered before the
+// handler serves requests. Wildcard matches such as "application/*" choose
+// the first registered media type that matches.
+//
+// Parameters:
+//   - mediaType: The media type, for example "application/msgpack".
+//   - encoder: The encoder for the media type.
+//
+// Returns:
+//   - *defaultNegotiatingOutputHandler: The handler.
+func (h *defaultNegotiatingOutputHandler) RegisterEncoder(
+	mediaType string, encoder endpointtypes.Encoder,
+) *defaultNegotiatingOutputHandler {
+	mediaType = strings.ToLower(mediaType)
+	if _, ok := h.encoders[mediaType]; !ok {
+		h.mediaTypes = append(h.mediaTypes, mediaType)
+	}
+	h.encoders[mediaType] = encoder
+	return h
+}
+
+// Handle writes the output with the negotiated encoder and sets the
+// Content-Type header to its media type. Errors are written in the envelope
+// of the handler's error envelope builder. If no registered media type is
+// acceptable, a 406 error is written as JSON. A nil
+// output with status 200 is written as 204 without a body.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//   - out: The output to write.
+//   - outputError: The optional error to write.
+//   - statusCode: The status code to write.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func (h *defaultNegotiatingOutputHandler) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out any,
+	outputError error,
+	statusCode int,
+) error {
+	w.Header().Add("Vary", "Accept")
+	mediaType, encoder, ok := h.negotiate(r.Header.Get("Accept"))
+	if !ok {
+		mediaType, encoder = MediaTypeJSON, NewJSONEncoder()
+		out = nil
+		outputError = util.NewAPIError(ErrIDNotAcceptable).
+			WithMessage("No acceptable media type")
+		statusCode = http.StatusNotAcceptable
+	}
+	var body any = out
+	if outputError != nil {
+		body = h.errorEnvelope.Build(r, outputError, statusCode)
+	} else if out == nil && statusCode == http.StatusOK {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	data, err := encoder.Encode(body)
+	if err != nil {
+		return fmt.Errorf("Handle: encode output: %w", err)
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("Handle: write output: %w", err)
+	}
+	return nil
+}
+
+// negotiate returns the registered media type and encoder that best match
+// the Accept header.
+func (h *defaultNegotiatingOutputHandler) negotiate(
+	accept string,
+) (string, endpointtypes.Encoder, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return h.first("*/*")
+	}
+	for _, accepted := range qualityValues(accept) {
+		mediaType, encoder, ok := h.first(strings.ToLower(accepted))
+		if ok {
+			return mediaType, encoder, true
+		}
+	}
+	return "", nil, false
+}
+
+// first returns the first registered media type that matches the pattern.
+// The pattern is a media type, a "type/*" wildcard or "*/*".
+func (h *defaultNegotiatingOutputHandler) first(
+	pattern string,
+) (string, endpointtypes.Encoder, bool) {
+	if encoder, ok := h.encoders[pattern]; ok {
+		return pattern, encoder, true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "*")
+	if !ok || !strings.HasSuffix(prefix, "/") {
+		return "", nil, false
+	}
+	for _, mediaType := range h.mediaTypes {
+		if prefix == "*/" || strings.HasPrefix(mediaType, prefix) {
+			return mediaType, h.encoders[mediaType], true
+		}
+	}
+	return "", nil, false
+}
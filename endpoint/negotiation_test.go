@@ -0,0 +1,143 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/util"
+	"github.com/stretchr/testify/suite"
+)
+
+// textEncoder encodes values with fmt for negotiation tests.
+type textEncoder struct{}
+
+func (e *textEncoder) Encode(v any) ([]byte, error) {
+	return []byte(fmt.Sprint(v)), nil
+}
+
+// NegotiatingOutputHandlerTestSuite tests the content negotiating output
+// handler.
+type NegotiatingOutputHandlerTestSuite struct {
+	suite.Suite
+}
+
+// TestNegotiatingOutputHandlerTestSuite runs the test suite.
+func TestNegotiatingOutputHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(NegotiatingOutputHandlerTestSuite))
+}
+
+// handle runs a content negotiating handler with the text encoder
+// registered and returns the recorded response.
+func (s *NegotiatingOutputHandlerTestSuite) handle(
+	accept string, out any, outputError error, statusCode int,
+) *httptest.ResponseRecorder {
+	handler := NewContentNegotiatingOutputHandler().
+		RegisterEncoder("text/x-test", &textEncoder{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	err := handler.Handle(rec, req, out, outputError, statusCode)
+	s.Require().NoError(err)
+	return rec
+}
+
+// Test_Handle_DefaultJSON tests that JSON is used without an Accept header.
+func (s *NegotiatingOutputHandlerTestSuite) Test_Handle_DefaultJSON() {
+	rec := s.handle("", map[string]int{"n": 1}, nil, http.StatusOK)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(MediaTypeJSON, rec.Header().Get("Content-Type"))
+	s.Equal("Accept", rec.Header().Get("Vary"))
+	s.Equal("{\"n\":1}\n", rec.Body.String())
+}
+
+// Test_Handle_RegisteredEncoder tests that the encoder of the preferred
+// accepted media type is used.
+func (s *NegotiatingOutputHandlerTestSuite) Test_Handle_RegisteredEncoder() {
+	rec := s.handle(
+		"application/json;q=0.5, text/x-test", "hello", nil, http.StatusOK,
+	)
+	s.Equal("text/x-test", rec.Header().Get("Content-Type"))
+	s.Equal("hello", rec.Body.String())
+}
+
+// Test_Handle_Wildcard tests that wildcards match registered media types.
+func (s *NegotiatingOutputHandlerTestSuite) Test_Handle_Wildcard() {
+	rec := s.handle("text/*", "hello", nil, http.StatusOK)
+	s.Equal("text/x-test", rec.Header().Get("Content-Type"))
+
+	rec = s.handle("image/png, */*;q=0.1", "hello", nil, http.StatusOK)
+	s.Equal(MediaTypeJSON, rec.Header().Get("Content-Type"))
+	s.Equal("\"hello\"\n", rec.Body.String())
+}
+
+// Test_Handle_NotAcceptable tests that 406 is written as JSON when no
+// registered media type is acceptable.
+func (s *NegotiatingOutputHandlerTestSuite) Test_Handle_NotAcceptable() {
+	rec := s.handle(
+		"image/png, application/json;q=0", "hello", nil, http.StatusOK,
+	)
+	s.Equal(http.StatusNotAcceptable, rec.Code)
+	s.Equal(MediaTypeJSON, rec.Header().Get("Content-Type"))
+	s.JSONEq(
+		`{"error":{"id":"not_acceptable",`+
+			`"message":"No acceptable media type"},"status":406}`,
+		rec.Body.String(),
+	)
+}
+
+// Test_Handle_Error tests that errors are written in an envelope with the
+// negotiated encoder.
+func (s *NegotiatingOutputHandlerTestSuite) Test_Handle_Error() {
+	apiErr := util.NewAPIError("not_found")
+	rec := s.handle(MediaTypeJSON, nil, apiErr, http.StatusNotFound)
+	s.Equal(http.StatusNotFound, rec.Code)
+	s.JSONEq(`{"error":{"id":"not_found"},"status":404}`, rec.Body.String())
+}
+
+// Test_Handle_ErrorEnvelope tests that errors are written with the
+// configured envelope builder and that the original handler is unchanged.
+func (s *NegotiatingOutputHandlerTestSuite) Test_Handle_ErrorEnvelope() {
+	base := NewContentNegotiatingOutputHandler()
+	handler := base.WithErrorEnvelope(
+		NewErrorEnvelopeBuilder().WithExposeInternal().WithTraceIDFn(
+			func(ctx context.Context) string { return "trace-1" },
+		),
+	).RegisterEncoder("text/x-test", &textEncoder{})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	rec := httptest.NewRecorder()
+	err := handler.Handle(
+		rec, req, nil, errors.New("secret"), http.StatusInternalServerError,
+	)
+	s.Require().NoError(err)
+	s.JSONEq(
+		`{"error":{"id":"internal_error","message":"secret"},`+
+			`"status":500,"trace_id":"trace-1"}`,
+		rec.Body.String(),
+	)
+
+	rec = httptest.NewRecorder()
+	err = base.Handle(
+		rec, req, nil, errors.New("secret"), http.StatusInternalServerError,
+	)
+	s.Require().NoError(err)
+	s.JSONEq(
+		`{"error":{"id":"internal_error"},"status":500}`, rec.Body.String(),
+	)
+	_, _, ok := base.negotiate("text/x-test")
+	s.False(ok)
+}
+
+// Test_Handle_NoContent tests that a nil output with status 200 is written
+// as 204.
+func (s *NegotiatingOutputHandlerTestSuite) Test_Handle_NoContent() {
+	rec := s.handle("text/x-test", nil, nil, http.StatusOK)
+	s.Equal(http.StatusNoContent, rec.Code)
+	s.Empty(rec.Body.String())
+}
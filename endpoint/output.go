@@ -0,0 +1,182 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	endpointtypes "github.com/pureapi/pureapi-core/endpoint/types"
+	"github.com/pureapi/pureapi-core/util"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
+)
+
+// JSONOutputOptions configures the JSON output handler.
+type JSONOutputOptions struct {
+	// Pretty indents the JSON output.
+	Pretty bool
+	// MessageResolver localizes error messages for the locales in the
+	// Accept-Language header. If nil, messages are written as is.
+	MessageResolver utiltypes.MessageResolver
+	// ErrorEnvelope builds the bodies written for errors. If nil,
+	// NewErrorEnvelopeBuilder is used.
+	ErrorEnvelope *ErrorEnvelopeBuilder
+}
+
+// defaultJSONOutputHandler writes endpoint responses as JSON.
+type defaultJSONOutputHandler struct {
+	pretty          bool
+	messageResolver utiltypes.MessageResolver
+	errorEnvelope   *ErrorEnvelopeBuilder
+}
+
+// defaultJSONOutputHandler implements the OutputHandler interface.
+var _ endpointtypes.OutputHandler = (*defaultJSONOutputHandler)(nil)
+
+// NewJSONOutputHandler creates a new JSON output handler.
+//
+// Parameters:
+//   - opts: The JSON output options.
+//
+// Returns:
+//   - *defaultJSONOutputHandler: A new defaultJSONOutputHandler instance.
+func NewJSONOutputHandler(opts JSONOutputOptions) *defaultJSONOutputHandler {
+	errorEnvelope := opts.ErrorEnvelope
+	if errorEnvelope == nil {
+		errorEnvelope = NewErrorEnvelopeBuilder()
+	}
+	return &defaultJSONOutputHandler{
+		pretty:          opts.Pretty,
+		messageResolver: opts.MessageResolver,
+		errorEnvelope:   errorEnvelope,
+	}
+}
+
+// Handle writes the output as JSON with the status code. If outputError is
+// set, an error envelope {"error": {...}, "status": ..., "trace_id": ...}
+// built by the error envelope builder is written instead. Error messages are
+// localized if a message resolver is set. A nil output with status 200 is
+// written as 204 without a body.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//   - out: The output to write.
+//   - outputError: The optional error to write.
+//   - statusCode: The status code to write.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func (h *defaultJSONOutputHandler) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out any,
+	outputError error,
+	statusCode int,
+) error {
+	var body any = out
+	if outputError != nil {
+		envelope := h.errorEnvelope.Build(r, outputError, statusCode)
+		envelope.Error = h.localize(r, envelope.Error)
+		body = envelope
+	} else if out == nil && statusCode == http.StatusOK {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	var data []byte
+	var err error
+	if h.pretty {
+		data, err = json.MarshalIndent(body, "", "  ")
+	} else {
+		data, err = json.Marshal(body)
+	}
+	if err != nil {
+		return fmt.Errorf("Handle: encode output: %w", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("Handle: write output: %w", err)
+	}
+	return nil
+}
+
+// localize sets the error message resolved for the first locale in the
+// Accept-Language header that has a message.
+func (h *defaultJSONOutputHandler) localize(
+	r *http.Request, apiErr *util.DefaultAPIError,
+) *util.DefaultAPIError {
+	if h.messageResolver == nil {
+		return apiErr
+	}
+	for _, locale := range acceptLanguages(r.Header.Get("Accept-Language")) {
+		message, ok := h.messageResolver.Resolve(
+			apiErr.ErrID, locale, apiErr.ErrData,
+		)
+		if ok {
+			return apiErr.WithMessage(message)
+		}
+	}
+	return apiErr
+}
+
+// acceptLanguages returns the locales of an Accept-Language header ordered
+// by their q-values. Wildcards and locales with q=0 are skipped.
+func acceptLanguages(header string) []string {
+	locales := []string{}
+	for _, locale := range qualityValues(header) {
+		if locale != "*" {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// qualityValues returns the values of a header with q-value parameters,
+// such as Accept or Accept-Language, ordered by their q-values. Values with
+// q=0 are skipped and other parameters are discarded.
+func qualityValues(header string) []string {
+	type qualityValue struct {
+		value string
+		q     float64
+	}
+	values := []qualityValue{}
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		q, ok := qualityParam(params)
+		if ok && q > 0 {
+			values = append(values, qualityValue{value: value, q: q})
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].q > values[j].q
+	})
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.value
+	}
+	return result
+}
+
+// qualityParam returns the q-value in the parameters of a header value. It
+// defaults to 1 and reports false if the q-value is malformed.
+func qualityParam(params string) (float64, bool) {
+	for _, param := range strings.Split(params, ";") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(param), "q=")
+		if !ok {
+			continue
+		}
+		q, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false
+		}
+		return q, true
+	}
+	return 1, true
+}
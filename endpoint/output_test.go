@@ -0,0 +1,142 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/util"
+	"github.com/stretchr/testify/suite"
+)
+
+// JSONOutputHandlerTestSuite tests the JSON output handler.
+type JSONOutputHandlerTestSuite struct {
+	suite.Suite
+}
+
+// TestJSONOutputHandlerTestSuite runs the test suite.
+func TestJSONOutputHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(JSONOutputHandlerTestSuite))
+}
+
+// Test_Handle_Success tests that the output is encoded with the status code.
+func (s *JSONOutputHandlerTestSuite) Test_Handle_Success() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	out := map[string]string{"name": "test"}
+	err := handler.Handle(rec, req, out, nil, http.StatusCreated)
+	s.Require().NoError(err)
+	s.Equal(http.StatusCreated, rec.Code)
+	s.Equal("application/json", rec.Header().Get("Content-Type"))
+	s.Equal("{\"name\":\"test\"}\n", rec.Body.String())
+}
+
+// Test_Handle_Pretty tests that the output is indented when requested.
+func (s *JSONOutputHandlerTestSuite) Test_Handle_Pretty() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{Pretty: true})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	out := map[string]string{"name": "test"}
+	err := handler.Handle(rec, req, out, nil, http.StatusOK)
+	s.Require().NoError(err)
+	s.Equal("{\n  \"name\": \"test\"\n}\n", rec.Body.String())
+}
+
+// Test_Handle_APIError tests that API errors are written in an envelope.
+func (s *JSONOutputHandlerTestSuite) Test_Handle_APIError() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	apiErr := util.NewAPIError("not_found").WithMessage("missing")
+	err := handler.Handle(rec, req, nil, apiErr, http.StatusNotFound)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, rec.Code)
+	s.Equal("application/json", rec.Header().Get("Content-Type"))
+	s.JSONEq(
+		`{"error":{"id":"not_found","message":"missing"},"status":404}`,
+		rec.Body.String(),
+	)
+}
+
+// Test_Handle_PlainError tests that non-API errors hide their message.
+func (s *JSONOutputHandlerTestSuite) Test_Handle_PlainError() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := handler.Handle(
+		rec, req, nil, errors.New("secret"), http.StatusInternalServerError,
+	)
+	s.Require().NoError(err)
+	s.Equal(http.StatusInternalServerError, rec.Code)
+	s.JSONEq(
+		`{"error":{"id":"internal_error"},"status":500}`, rec.Body.String(),
+	)
+}
+
+// Test_Handle_Localized tests that error messages are localized using the
+// Accept-Language header and fall back to the raw message.
+func (s *JSONOutputHandlerTestSuite) Test_Handle_Localized() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{
+		MessageResolver: util.NewMapMessageResolver(
+			map[string]map[string]string{
+				"fi": {"not_found": "Ei löytynyt"},
+				"sv": {"not_found": "Hittades inte"},
+			},
+		),
+	})
+	apiErr := util.NewAPIError("not_found").WithMessage("Not found")
+	testCases := []struct {
+		header   string
+		expected string
+	}{
+		{"sv;q=0.5, fi-FI;q=0.8, de", "Ei löytynyt"},
+		{"sv, fi;q=0", "Hittades inte"},
+		{"de, *", "Not found"},
+		{"", "Not found"},
+	}
+
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", tc.header)
+
+		err := handler.Handle(rec, req, nil, apiErr, http.StatusNotFound)
+		s.Require().NoError(err)
+		s.JSONEq(
+			`{"error":{"id":"not_found","message":"`+tc.expected+`"},`+
+				`"status":404}`,
+			rec.Body.String(),
+			tc.header,
+		)
+	}
+}
+
+// Test_Handle_NilBody tests that a nil output is written as 204.
+func (s *JSONOutputHandlerTestSuite) Test_Handle_NilBody() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := handler.Handle(rec, req, nil, nil, http.StatusOK)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, rec.Code)
+	s.Empty(rec.Body.String())
+	s.Empty(rec.Header().Get("Content-Type"))
+}
+
+// Test_Handle_EncodeError tests that encoding failures are returned.
+func (s *JSONOutputHandlerTestSuite) Test_Handle_EncodeError() {
+	handler := NewJSONOutputHandler(JSONOutputOptions{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := handler.Handle(rec, req, make(chan int), nil, http.StatusOK)
+	s.Require().Error(err)
+	s.Empty(rec.Body.String())
+}
@@ -1,13 +1,19 @@
 package endpoint
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/pureapi/pureapi-core/endpoint/types"
 )
 
+// ErrDuplicateWrapper is returned when a wrapper with the same ID already
+// exists in the stack.
+var ErrDuplicateWrapper = errors.New("duplicate wrapper ID")
+
 // defaultStack manages a list of middleware wrappers with concurrency safety
-// for editing the list.
+// for editing the list. Wrapper IDs are unique within a stack.
 type defaultStack struct {
 	mu       sync.RWMutex
 	wrappers []types.Wrapper
@@ -16,7 +22,8 @@ type defaultStack struct {
 // defaultStack implements the Stack interface.
 var _ types.Stack = (*defaultStack)(nil)
 
-// NewStack creates and returns an initialized defaultStack.
+// NewStack creates and returns an initialized defaultStack. A wrapper whose
+// ID is already in the list replaces the earlier one in place.
 //
 // Parameters:
 //   - wrappers: The initial list of middleware wrappers.
@@ -24,10 +31,14 @@ var _ types.Stack = (*defaultStack)(nil)
 // Returns:
 //   - *defaultStack: A new defaultStack instance.
 func NewStack(wrappers ...types.Wrapper) *defaultStack {
-	return &defaultStack{
+	stack := &defaultStack{
 		mu:       sync.RWMutex{},
-		wrappers: wrappers,
+		wrappers: []types.Wrapper{},
+	}
+	for _, wrapper := range wrappers {
+		stack.AddWrapper(wrapper)
 	}
+	return stack
 }
 
 // Wrappers returns the list of middleware wrappers in the stack.
@@ -67,8 +78,39 @@ func (s *defaultStack) Clone() types.Stack {
 	return newStack
 }
 
-// Add appends a new middleware Wrapper to the stack and returns the stack for
-// chaining.
+// Get returns the middleware Wrapper with the specified ID.
+//
+// Parameters:
+//   - id: The ID of the wrapper to get.
+//
+// Returns:
+//   - Wrapper: The wrapper, or nil if not found.
+//   - bool: True if the wrapper was found.
+func (s *defaultStack) Get(id string) (types.Wrapper, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.indexOf(id); i >= 0 {
+		return s.wrappers[i], true
+	}
+	return nil, false
+}
+
+// Has reports whether the stack contains a middleware Wrapper with the
+// specified ID.
+//
+// Parameters:
+//   - id: The ID of the wrapper to look for.
+//
+// Returns:
+//   - bool: True if the wrapper was found.
+func (s *defaultStack) Has(id string) bool {
+	_, found := s.Get(id)
+	return found
+}
+
+// AddWrapper appends a new middleware Wrapper to the stack and returns the
+// stack for chaining. If a wrapper with the same ID exists, it is replaced in
+// place.
 //
 // Parameters:
 //   - w: The wrapper to add.
@@ -78,14 +120,41 @@ func (s *defaultStack) Clone() types.Stack {
 func (s *defaultStack) AddWrapper(w types.Wrapper) types.Stack {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if i := s.indexOf(w.ID()); i >= 0 {
+		s.wrappers[i] = w
+		return s
+	}
 	s.wrappers = append(s.wrappers, w)
 	return s
 }
 
+// AddWrapperUnique appends a new middleware Wrapper to the stack. If a
+// wrapper with the same ID exists, the stack is left unchanged and an error
+// wrapping ErrDuplicateWrapper is returned.
+//
+// Parameters:
+//   - w: The wrapper to add.
+//
+// Returns:
+//   - *Stack: The middleware stack.
+//   - error: An error if the ID already exists.
+func (s *defaultStack) AddWrapperUnique(w types.Wrapper) (types.Stack, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.indexOf(w.ID()) >= 0 {
+		return s, fmt.Errorf(
+			"AddWrapperUnique: %w: %s", ErrDuplicateWrapper, w.ID(),
+		)
+	}
+	s.wrappers = append(s.wrappers, w)
+	return s, nil
+}
+
 // InsertBefore inserts a middleware Wrapper before the one with the specified
 // ID. Returns true if a matching wrapper was found and insertion happened
 // before it; if no match is found, the new wrapper is appended and false is
-// returned.
+// returned. An existing wrapper with the same ID as w is moved to the new
+// position.
 //
 // Parameters:
 //   - id: The ID of the wrapper to insert before.
@@ -97,24 +166,13 @@ func (s *defaultStack) AddWrapper(w types.Wrapper) types.Stack {
 func (s *defaultStack) InsertBefore(
 	id string, w types.Wrapper,
 ) (types.Stack, bool) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	for i, wrapper := range s.wrappers {
-		if wrapper.ID() == id {
-			s.wrappers = append(
-				s.wrappers[:i],
-				append([]types.Wrapper{w}, s.wrappers[i:]...)...,
-			)
-			return s, true
-		}
-	}
-	s.wrappers = append(s.wrappers, w)
-	return s, false
+	return s.insertAt(id, w, 0)
 }
 
 // InsertAfter inserts a middleware Wrapper after the one with the specified ID.
 // Returns true if a matching wrapper was found and insertion happened after it.
 // If no match is found, the new wrapper is appended and false is returned.
+// An existing wrapper with the same ID as w is moved to the new position.
 //
 // Parameters:
 //   - id: The ID of the wrapper to insert after.
@@ -126,20 +184,7 @@ func (s *defaultStack) InsertBefore(
 func (s *defaultStack) InsertAfter(
 	id string, w types.Wrapper,
 ) (types.Stack, bool) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	for i, wrapper := range s.wrappers {
-		if wrapper.ID() == id {
-			pos := i + 1
-			s.wrappers = append(
-				s.wrappers[:pos],
-				append([]types.Wrapper{w}, s.wrappers[pos:]...)...,
-			)
-			return s, true
-		}
-	}
-	s.wrappers = append(s.wrappers, w)
-	return s, false
+	return s.insertAt(id, w, 1)
 }
 
 // Remove deletes the middleware Wrapper with the specified ID from the stack.
@@ -162,3 +207,42 @@ func (s *defaultStack) Remove(id string) (types.Stack, bool) {
 	}
 	return s, false
 }
+
+// insertAt inserts w at the given offset from the wrapper with the specified
+// ID, removing any existing wrapper with the same ID as w first. If w has the
+// specified ID itself, it replaces that wrapper in place.
+func (s *defaultStack) insertAt(
+	id string, w types.Wrapper, offset int,
+) (types.Stack, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w.ID() == id {
+		if i := s.indexOf(id); i >= 0 {
+			s.wrappers[i] = w
+			return s, true
+		}
+	} else if i := s.indexOf(w.ID()); i >= 0 {
+		s.wrappers = append(s.wrappers[:i], s.wrappers[i+1:]...)
+	}
+	if i := s.indexOf(id); i >= 0 {
+		pos := i + offset
+		s.wrappers = append(
+			s.wrappers[:pos],
+			append([]types.Wrapper{w}, s.wrappers[pos:]...)...,
+		)
+		return s, true
+	}
+	s.wrappers = append(s.wrappers, w)
+	return s, false
+}
+
+// indexOf returns the index of the wrapper with the specified ID, or -1 if
+// not found. The caller must hold the lock.
+func (s *defaultStack) indexOf(id string) int {
+	for i, wrapper := range s.wrappers {
+		if wrapper.ID() == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -178,3 +178,94 @@ func (s *StackTestSuite) TestRemove() {
 	// The stack remains unchanged.
 	s.Require().Len(updated.Wrappers(), 2)
 }
+
+// TestAddWrapper_ReplacesDuplicate verifies that AddWrapper replaces a wrapper
+// with the same ID in place.
+func (s *StackTestSuite) TestAddWrapper_ReplacesDuplicate() {
+	var events []string
+	stack := NewStack(
+		NewWrapper("auth", noopMiddleware),
+		NewWrapper("log", noopMiddleware),
+	)
+	stack.AddWrapper(NewWrapper("auth", makeTestMiddleware("new", &events)))
+
+	wrappers := stack.Wrappers()
+	s.Require().Len(wrappers, 2)
+	s.Equal("auth", wrappers[0].ID())
+	s.Equal("log", wrappers[1].ID())
+	stack.Middlewares().Chain(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	)).ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil),
+	)
+	s.Equal([]string{"new-pre", "new-post"}, events)
+}
+
+// TestNewStack_Duplicates verifies that NewStack keeps IDs unique.
+func (s *StackTestSuite) TestNewStack_Duplicates() {
+	stack := NewStack(
+		NewWrapper("w1", noopMiddleware),
+		NewWrapper("w2", noopMiddleware),
+		NewWrapper("w1", noopMiddleware),
+	)
+	s.Len(stack.Wrappers(), 2)
+}
+
+// TestAddWrapperUnique verifies that AddWrapperUnique returns an error for a
+// duplicate ID and leaves the stack unchanged.
+func (s *StackTestSuite) TestAddWrapperUnique() {
+	stack := NewStack(NewWrapper("auth", noopMiddleware))
+
+	_, err := stack.AddWrapperUnique(NewWrapper("log", noopMiddleware))
+	s.Require().NoError(err)
+	s.Len(stack.Wrappers(), 2)
+
+	_, err = stack.AddWrapperUnique(NewWrapper("auth", noopMiddleware))
+	s.ErrorIs(err, ErrDuplicateWrapper)
+	s.Len(stack.Wrappers(), 2)
+}
+
+// TestInsert_MovesDuplicate verifies that InsertBefore and InsertAfter move an
+// existing wrapper with the same ID instead of adding a second one.
+func (s *StackTestSuite) TestInsert_MovesDuplicate() {
+	stack := NewStack(
+		NewWrapper("w1", noopMiddleware),
+		NewWrapper("w2", noopMiddleware),
+		NewWrapper("w3", noopMiddleware),
+	)
+	ids := func() []string {
+		var ids []string
+		for _, w := range stack.Wrappers() {
+			ids = append(ids, w.ID())
+		}
+		return ids
+	}
+
+	_, found := stack.InsertBefore("w1", NewWrapper("w3", noopMiddleware))
+	s.True(found)
+	s.Equal([]string{"w3", "w1", "w2"}, ids())
+
+	_, found = stack.InsertAfter("w2", NewWrapper("w3", noopMiddleware))
+	s.True(found)
+	s.Equal([]string{"w1", "w2", "w3"}, ids())
+
+	_, found = stack.InsertAfter("w2", NewWrapper("w2", noopMiddleware))
+	s.True(found)
+	s.Equal([]string{"w1", "w2", "w3"}, ids())
+}
+
+// TestGetHas verifies that Get and Has find wrappers by ID.
+func (s *StackTestSuite) TestGetHas() {
+	w1 := NewWrapper("w1", noopMiddleware)
+	stack := NewStack(w1)
+
+	wrapper, found := stack.Get("w1")
+	s.True(found)
+	s.Equal(w1, wrapper)
+	s.True(stack.Has("w1"))
+
+	wrapper, found = stack.Get("missing")
+	s.False(found)
+	s.Nil(wrapper)
+	s.False(stack.Has("missing"))
+}
@@ -0,0 +1,6 @@
+package types
+
+// Encoder encodes endpoint output for a media type.
+type Encoder interface {
+	Encode(v any) ([]byte, error)
+}
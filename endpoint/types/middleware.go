@@ -17,6 +17,7 @@ type Middleware func(http.Handler) http.Handler
 // Middlewares is a collection of Middleware functions.
 type Middlewares interface {
 	Chain(h http.Handler) http.Handler
+	Reverse() Middlewares
 }
 
 // Wrapper is an interface for a middleware wrapper. It encapsulates a
@@ -32,7 +33,10 @@ type Stack interface {
 	Wrappers() []Wrapper
 	Middlewares() Middlewares
 	Clone() Stack
+	Get(id string) (Wrapper, bool)
+	Has(id string) bool
 	AddWrapper(w Wrapper) Stack
+	AddWrapperUnique(w Wrapper) (Stack, error)
 	InsertBefore(id string, w Wrapper) (Stack, bool)
 	InsertAfter(id string, w Wrapper) (Stack, bool)
 	Remove(id string) (Stack, bool)
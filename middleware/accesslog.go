@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+	"github.com/pureapi/pureapi-core/util"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
+)
+
+// Define events.
+const (
+	EventAccessLog utiltypes.EventType = "event_access_log"
+)
+
+// AccessLog returns a middleware that emits an EventAccessLog event at the
+// Info level after each request. The event data holds the method, path,
+// status code, bytes written, duration and, if present, the request ID.
+//
+// Parameters:
+//   - emitterLogger: The emitter logger to use.
+//
+// Returns:
+//   - types.Middleware: The access log middleware.
+func AccessLog(emitterLogger utiltypes.EmitterLogger) types.Middleware {
+	if emitterLogger == nil {
+		emitterLogger = util.NewNoopEmitterLogger()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := util.NewResWrap(w).WithSizeOnly()
+			next.ServeHTTP(rw, r)
+			duration := time.Since(start)
+			data := map[string]any{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"status_code": rw.StatusCode(),
+				"bytes":       rw.BytesWritten(),
+				"duration":    duration,
+			}
+			requestID := RequestIDFromContext(r.Context())
+			if requestID == "" {
+				requestID = w.Header().Get(DefaultRequestIDHeader)
+			}
+			if requestID != "" {
+				data["request_id"] = requestID
+			}
+			emitterLogger.Info(
+				utiltypes.NewEvent(
+					EventAccessLog,
+					fmt.Sprintf(
+						"%s %s %d %dB %s",
+						r.Method,
+						r.URL.Path,
+						rw.StatusCode(),
+						rw.BytesWritten(),
+						duration,
+					),
+				).WithData(data),
+			)
+		})
+	}
+}
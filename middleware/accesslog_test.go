@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingEmitterLogger records the events passed to it.
+type recordingEmitterLogger struct {
+	events []*utiltypes.Event
+}
+
+func (l *recordingEmitterLogger) Debug(e *utiltypes.Event, _ ...any) {
+	l.events = append(l.events, e)
+}
+func (l *recordingEmitterLogger) Info(e *utiltypes.Event, _ ...any) {
+	l.events = append(l.events, e)
+}
+func (l *recordingEmitterLogger) Warn(e *utiltypes.Event, _ ...any) {
+	l.events = append(l.events, e)
+}
+func (l *recordingEmitterLogger) Error(e *utiltypes.Event, _ ...any) {
+	l.events = append(l.events, e)
+}
+func (l *recordingEmitterLogger) Fatal(e *utiltypes.Event, _ ...any) {
+	l.events = append(l.events, e)
+}
+func (l *recordingEmitterLogger) Trace(e *utiltypes.Event, _ ...any) {
+	l.events = append(l.events, e)
+}
+
+// AccessLogTestSuite is a suite of tests for the access log middleware.
+type AccessLogTestSuite struct {
+	suite.Suite
+}
+
+// TestAccessLogTestSuite runs the test suite.
+func TestAccessLogTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessLogTestSuite))
+}
+
+// Test_Event tests that the emitted event carries the status code, byte
+// count and request ID.
+func (s *AccessLogTestSuite) Test_Event() {
+	logger := &recordingEmitterLogger{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("hello"))
+	})
+	handler := RequestID(RequestIDOptions{})(AccessLog(logger)(next))
+	r := httptest.NewRequest(http.MethodPost, "/items", nil)
+	r.Header.Set(DefaultRequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	s.Require().Len(logger.events, 1)
+	event := logger.events[0]
+	s.Equal(EventAccessLog, event.Type)
+	data := event.Data.(map[string]any)
+	s.Equal(http.MethodPost, data["method"])
+	s.Equal("/items", data["path"])
+	s.Equal(http.StatusAccepted, data["status_code"])
+	s.Equal(5, data["bytes"])
+	s.Equal("req-1", data["request_id"])
+	s.IsType(time.Duration(0), data["duration"])
+	s.Contains(event.Message, "POST /items 202 5B")
+}
+
+// Test_NoRequestID tests that the request ID is omitted when not present.
+func (s *AccessLogTestSuite) Test_NoRequestID() {
+	logger := &recordingEmitterLogger{}
+	handler := AccessLog(logger)(okHandler(nil))
+	handler.ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil),
+	)
+
+	s.Require().Len(logger.events, 1)
+	data := logger.events[0].Data.(map[string]any)
+	s.Equal(http.StatusOK, data["status_code"])
+	s.Equal(2, data["bytes"])
+	s.NotContains(data, "request_id")
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+	middlewaretypes "github.com/pureapi/pureapi-core/middleware/types"
+)
+
+// ClaimsKey is the context key under which the verified token claims are
+// stored.
+const ClaimsKey contextKey = "claims"
+
+// Auth returns a middleware that requires a valid bearer token in the
+// Authorization header. The token is verified with the verifier and the
+// claims are stored in the request context under ClaimsKey. Requests with a
+// missing, malformed or invalid token are rejected with 401.
+//
+// Exempt paths are matched exactly, or by prefix if they end with "*".
+//
+// Parameters:
+//   - verifier: The token verifier.
+//   - exemptPaths: The optional paths that do not require a token.
+//
+// Returns:
+//   - types.Middleware: The authentication middleware.
+func Auth(
+	verifier middlewaretypes.TokenVerifier, exemptPaths ...string,
+) types.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isExemptPath(r.URL.Path, exemptPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			token, ok := bearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				unauthorized(w)
+				return
+			}
+			claims, err := verifier.Verify(r.Context(), token)
+			if err != nil {
+				unauthorized(w)
+				return
+			}
+			ctx := context.WithValue(r.Context(), ClaimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClaimsFromContext returns the token claims stored in the context.
+//
+// Parameters:
+//   - ctx: The context to read from.
+//
+// Returns:
+//   - middlewaretypes.Claims: The claims, or nil if none are set.
+//   - bool: Whether claims were found.
+func ClaimsFromContext(ctx context.Context) (middlewaretypes.Claims, bool) {
+	claims, ok := ctx.Value(ClaimsKey).(middlewaretypes.Claims)
+	return claims, ok
+}
+
+// bearerToken extracts the token from a bearer Authorization header value.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return "", false
+	}
+	return token, true
+}
+
+// isExemptPath reports whether the path matches one of the exempt paths.
+func isExemptPath(path string, exemptPaths []string) bool {
+	if slices.Contains(exemptPaths, path) {
+		return true
+	}
+	for _, exempt := range exemptPaths {
+		if prefix, ok := strings.CutSuffix(exempt, "*"); ok &&
+			strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unauthorized writes a 401 response with a bearer challenge.
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(
+		w,
+		http.StatusText(http.StatusUnauthorized),
+		http.StatusUnauthorized,
+	)
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	middlewaretypes "github.com/pureapi/pureapi-core/middleware/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeVerifier implements types.TokenVerifier for a single valid token.
+type fakeVerifier struct {
+	validToken string
+	calls      int
+}
+
+func (v *fakeVerifier) Verify(
+	_ context.Context, token string,
+) (middlewaretypes.Claims, error) {
+	v.calls++
+	if token != v.validToken {
+		return nil, errors.New("invalid token")
+	}
+	return middlewaretypes.Claims{"sub": "user-1"}, nil
+}
+
+// AuthTestSuite is a suite of tests for the authentication middleware.
+type AuthTestSuite struct {
+	suite.Suite
+	verifier *fakeVerifier
+}
+
+// TestAuthTestSuite runs the test suite.
+func TestAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthTestSuite))
+}
+
+// SetupTest creates a new verifier.
+func (s *AuthTestSuite) SetupTest() {
+	s.verifier = &fakeVerifier{validToken: "good"}
+}
+
+// serve sends a request with the Authorization header to path and returns
+// the recorder and the claims seen by the next handler.
+func (s *AuthTestSuite) serve(
+	path string, authorization string,
+) (*httptest.ResponseRecorder, middlewaretypes.Claims) {
+	var seen middlewaretypes.Claims
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = ClaimsFromContext(r.Context())
+	})
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	Auth(s.verifier, "/health", "/public/*")(next).ServeHTTP(rec, r)
+	return rec, seen
+}
+
+// Test_Valid tests that a valid token passes and stores the claims.
+func (s *AuthTestSuite) Test_Valid() {
+	rec, claims := s.serve("/secure", "Bearer good")
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(middlewaretypes.Claims{"sub": "user-1"}, claims)
+}
+
+// Test_Rejected tests missing, malformed and invalid tokens.
+func (s *AuthTestSuite) Test_Rejected() {
+	tests := []struct {
+		name          string
+		authorization string
+		verifyCalled  bool
+	}{
+		{"missing header", "", false},
+		{"wrong scheme", "Basic good", false},
+		{"no token", "Bearer ", false},
+		{"malformed token", "Bearer go od", false},
+		{"verify failure", "Bearer bad", true},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			rec, claims := s.serve("/secure", tt.authorization)
+			s.Equal(http.StatusUnauthorized, rec.Code)
+			s.Equal("Bearer", rec.Header().Get("WWW-Authenticate"))
+			s.Nil(claims)
+			s.Equal(tt.verifyCalled, s.verifier.calls > 0)
+		})
+	}
+}
+
+// Test_Exempt tests that exempt paths skip authentication.
+func (s *AuthTestSuite) Test_Exempt() {
+	for _, path := range []string{"/health", "/public/docs"} {
+		rec, _ := s.serve(path, "")
+		s.Equal(http.StatusOK, rec.Code, path)
+	}
+	rec, _ := s.serve("/healthz", "")
+	s.Equal(http.StatusUnauthorized, rec.Code)
+}
+
+// Test_ClaimsFromContext_Missing tests that no claims are found in an empty
+// context.
+func (s *AuthTestSuite) Test_ClaimsFromContext_Missing() {
+	claims, ok := ClaimsFromContext(context.Background())
+	s.False(ok)
+	s.Nil(claims)
+}
@@ -0,0 +1,301 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// DefaultCompressMinSize is the minimum body size in bytes that is compressed
+// when none is configured.
+const DefaultCompressMinSize = 1024
+
+// DefaultCompressSkipTypes are the content type prefixes that are never
+// compressed when none are configured, since they are usually compressed
+// already.
+var DefaultCompressSkipTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zstd",
+	"application/octet-stream",
+}
+
+// Supported content encodings.
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// CompressOptions configures the compression middleware.
+type CompressOptions struct {
+	// Level is the compression level. Defaults to the default level of the
+	// compressor.
+	Level int
+	// MinSize is the minimum body size in bytes that is compressed. Defaults
+	// to DefaultCompressMinSize. Use a negative value to compress all bodies.
+	MinSize int
+	// EnableDeflate allows the deflate encoding in addition to gzip.
+	EnableDeflate bool
+	// SkipContentTypes lists the content type prefixes that are never
+	// compressed. Defaults to DefaultCompressSkipTypes.
+	SkipContentTypes []string
+}
+
+// Compress returns a middleware that compresses response bodies with gzip, or
+// deflate if enabled, based on the Accept-Encoding request header. Bodies
+// smaller than the minimum size, already encoded bodies, skipped content
+// types and HEAD requests are sent uncompressed.
+//
+// Parameters:
+//   - opts: The compression options.
+//
+// Returns:
+//   - types.Middleware: The compression middleware.
+func Compress(opts CompressOptions) types.Middleware {
+	if opts.Level == 0 {
+		opts.Level = gzip.DefaultCompression
+	}
+	if opts.MinSize == 0 {
+		opts.MinSize = DefaultCompressMinSize
+	}
+	if opts.SkipContentTypes == nil {
+		opts.SkipContentTypes = DefaultCompressSkipTypes
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateEncoding(
+				r.Header.Get("Accept-Encoding"), opts.EnableDeflate,
+			)
+			if r.Method == http.MethodHead || encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				opts:           &opts,
+				status:         http.StatusOK,
+			}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressWriter buffers the start of the body until it can decide whether to
+// compress it.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	opts        *CompressOptions
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	compressor  io.WriteCloser
+}
+
+// WriteHeader records the status code. It is sent once the writer has decided
+// whether to compress.
+//
+// Parameters:
+//   - status: The status code.
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+}
+
+// Write buffers or compresses the data.
+//
+// Parameters:
+//   - p: The data to write.
+//
+// Returns:
+//   - int: The number of bytes of p consumed.
+//   - error: An error if writing fails.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	cw.wroteHeader = true
+	if !cw.decided {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) < cw.opts.MinSize {
+			return len(p), nil
+		}
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.compressor != nil {
+		return cw.compressor.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush decides whether to compress, flushes the compressor and then the
+// underlying writer.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if err := cw.decide(); err != nil {
+			return
+		}
+	}
+	if f, ok := cw.compressor.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close sends any buffered data and closes the compressor.
+//
+// Returns:
+//   - error: An error if writing or closing fails.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			cw.decided = true
+			return nil
+		}
+		if len(cw.buf) < cw.opts.MinSize {
+			cw.decided = true
+			return cw.writeBuffered()
+		}
+		if err := cw.decide(); err != nil {
+			return err
+		}
+	}
+	if cw.compressor != nil {
+		return cw.compressor.Close()
+	}
+	return nil
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+//
+// Returns:
+//   - http.ResponseWriter: The underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// decide sets up compression if the response qualifies and writes the
+// buffered data.
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+	header := cw.Header()
+	if header.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	if !cw.shouldCompress() {
+		return cw.writeBuffered()
+	}
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	var err error
+	switch cw.encoding {
+	case encodingGzip:
+		cw.compressor, err = gzip.NewWriterLevel(
+			cw.ResponseWriter, cw.opts.Level,
+		)
+	default:
+		cw.compressor, err = zlib.NewWriterLevel(
+			cw.ResponseWriter, cw.opts.Level,
+		)
+	}
+	if err != nil {
+		header.Del("Content-Encoding")
+		cw.compressor = nil
+		return cw.writeBuffered()
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	_, err = cw.compressor.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// writeBuffered writes the status and buffered data uncompressed.
+func (cw *compressWriter) writeBuffered() error {
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	_, err := cw.ResponseWriter.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// shouldCompress reports whether the response qualifies for compression.
+func (cw *compressWriter) shouldCompress() bool {
+	if cw.status < http.StatusOK ||
+		cw.status == http.StatusNoContent ||
+		cw.status == http.StatusNotModified {
+		return false
+	}
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, skip := range cw.opts.SkipContentTypes {
+		if strings.HasPrefix(contentType, skip) {
+			return false
+		}
+	}
+	return true
+}
+
+// negotiateEncoding picks the preferred supported encoding from an
+// Accept-Encoding header value. It returns an empty string if none is
+// acceptable.
+func negotiateEncoding(acceptEncoding string, enableDeflate bool) string {
+	qValues := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		qValues[name] = q
+	}
+	qFor := func(name string) float64 {
+		if q, ok := qValues[name]; ok {
+			return q
+		}
+		return qValues["*"]
+	}
+	gzipQ := qFor(encodingGzip)
+	deflateQ := 0.0
+	if enableDeflate {
+		deflateQ = qFor(encodingDeflate)
+	}
+	switch {
+	case gzipQ > 0 && gzipQ >= deflateQ:
+		return encodingGzip
+	case deflateQ > 0:
+		return encodingDeflate
+	default:
+		return ""
+	}
+}
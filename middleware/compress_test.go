@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// CompressTestSuite is a suite of tests for the compression middleware.
+type CompressTestSuite struct {
+	suite.Suite
+}
+
+// TestCompressTestSuite runs the test suite.
+func TestCompressTestSuite(t *testing.T) {
+	suite.Run(t, new(CompressTestSuite))
+}
+
+// serve runs a request with the given Accept-Encoding through a compression
+// middleware wrapping a handler that writes body with contentType.
+func (s *CompressTestSuite) serve(
+	opts CompressOptions,
+	method string,
+	acceptEncoding string,
+	contentType string,
+	body string,
+) *httptest.ResponseRecorder {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, body)
+	})
+	r := httptest.NewRequest(method, "/", nil)
+	if acceptEncoding != "" {
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	Compress(opts)(next).ServeHTTP(rec, r)
+	return rec
+}
+
+// Test_Gzip tests that a large JSON body is gzip compressed and round-trips.
+func (s *CompressTestSuite) Test_Gzip() {
+	body := strings.Repeat(`{"name":"value"},`, 200)
+	rec := s.serve(
+		CompressOptions{}, http.MethodGet, "gzip", "application/json", body,
+	)
+
+	s.Equal(http.StatusCreated, rec.Code)
+	s.Equal("gzip", rec.Header().Get("Content-Encoding"))
+	s.Contains(rec.Header().Values("Vary"), "Accept-Encoding")
+	s.Less(rec.Body.Len(), len(body))
+	reader, err := gzip.NewReader(rec.Body)
+	s.Require().NoError(err)
+	decoded, err := io.ReadAll(reader)
+	s.Require().NoError(err)
+	s.Equal(body, string(decoded))
+}
+
+// Test_Deflate tests that deflate is used when enabled and preferred.
+func (s *CompressTestSuite) Test_Deflate() {
+	body := strings.Repeat("a", 2048)
+	rec := s.serve(
+		CompressOptions{EnableDeflate: true},
+		http.MethodGet,
+		"gzip;q=0.5, deflate",
+		"text/plain",
+		body,
+	)
+	s.Equal("deflate", rec.Header().Get("Content-Encoding"))
+	zr, err := zlib.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	s.Require().NoError(err)
+	defer zr.Close()
+	decoded, err := io.ReadAll(zr)
+	s.Require().NoError(err)
+	s.Equal(body, string(decoded))
+}
+
+// Test_NotCompressed tests the cases where the body is sent uncompressed.
+func (s *CompressTestSuite) Test_NotCompressed() {
+	large := strings.Repeat("a", 2048)
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		contentType    string
+		body           string
+	}{
+		{"head", http.MethodHead, "gzip", "text/plain", large},
+		{"no accept", http.MethodGet, "", "text/plain", large},
+		{"gzip rejected", http.MethodGet, "gzip;q=0, *", "text/plain", large},
+		{"small body", http.MethodGet, "gzip", "text/plain", "small"},
+		{"skipped type", http.MethodGet, "gzip", "image/png", large},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			rec := s.serve(
+				CompressOptions{},
+				tt.method,
+				tt.acceptEncoding,
+				tt.contentType,
+				tt.body,
+			)
+			s.Equal(http.StatusCreated, rec.Code)
+			s.Empty(rec.Header().Get("Content-Encoding"))
+			if tt.method != http.MethodHead {
+				s.Equal(tt.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+// Test_Flush tests that flushing compresses the buffered data so far.
+func (s *CompressTestSuite) Test_Flush() {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "part1")
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, "part2")
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	Compress(CompressOptions{})(next).ServeHTTP(rec, r)
+
+	s.True(rec.Flushed)
+	s.Equal("gzip", rec.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(rec.Body)
+	s.Require().NoError(err)
+	decoded, err := io.ReadAll(reader)
+	s.Require().NoError(err)
+	s.Equal("part1part2", string(decoded))
+}
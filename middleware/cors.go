@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// DefaultCORSMethods are the methods allowed when none are configured.
+var DefaultCORSMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost,
+}
+
+// CORSOptions configures the CORS middleware.
+type CORSOptions struct {
+	// AllowedOrigins lists the allowed origins. "*" allows any origin and a
+	// single "*" inside an origin matches any subdomain part, e.g.
+	// "https://*.example.com".
+	AllowedOrigins []string
+	// AllowedOriginPatterns lists regular expressions matched against the
+	// origin in addition to AllowedOrigins.
+	AllowedOriginPatterns []*regexp.Regexp
+	// AllowedMethods lists the allowed methods. Defaults to
+	// DefaultCORSMethods.
+	AllowedMethods []string
+	// AllowedHeaders lists the allowed request headers. "*" allows any
+	// header.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers exposed to the client.
+	ExposedHeaders []string
+	// AllowCredentials allows cookies and credentials for origins matched by
+	// the configured origins or patterns, echoing the request origin. Origins
+	// only allowed by "*" get "*" without credentials, so credentials are
+	// never granted to arbitrary origins.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache preflight results. Zero omits
+	// the header.
+	MaxAge time.Duration
+}
+
+// cors holds the prepared CORS configuration.
+type cors struct {
+	allowAllOrigins  bool
+	origins          []string
+	wildcardOrigins  [][2]string
+	originPatterns   []*regexp.Regexp
+	methods          []string
+	allowAllHeaders  bool
+	headers          []string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+// CORS returns a middleware that applies Cross-Origin Resource Sharing
+// headers. Preflight requests are answered with 204 without calling the next
+// handler.
+//
+// Parameters:
+//   - opts: The CORS options.
+//
+// Returns:
+//   - types.Middleware: The CORS middleware.
+func CORS(opts CORSOptions) types.Middleware {
+	c := newCORS(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions &&
+				r.Header.Get("Access-Control-Request-Method") != "" {
+				c.handlePreflight(w, r)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			c.handleSimple(w, r)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// newCORS prepares the CORS configuration from the options.
+func newCORS(opts CORSOptions) *cors {
+	c := &cors{
+		originPatterns:   opts.AllowedOriginPatterns,
+		exposedHeaders:   strings.Join(opts.ExposedHeaders, ", "),
+		allowCredentials: opts.AllowCredentials,
+	}
+	for _, origin := range opts.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		switch {
+		case origin == "*":
+			c.allowAllOrigins = true
+		case strings.Count(origin, "*") == 1:
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			c.wildcardOrigins = append(
+				c.wildcardOrigins, [2]string{prefix, suffix},
+			)
+		default:
+			c.origins = append(c.origins, origin)
+		}
+	}
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	for _, method := range methods {
+		c.methods = append(c.methods, strings.ToUpper(method))
+	}
+	for _, header := range opts.AllowedHeaders {
+		if header == "*" {
+			c.allowAllHeaders = true
+			continue
+		}
+		c.headers = append(c.headers, http.CanonicalHeaderKey(header))
+	}
+	if opts.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(opts.MaxAge.Seconds()))
+	}
+	return c
+}
+
+// handlePreflight sets the headers for a preflight request.
+func (c *cors) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+	origin := r.Header.Get("Origin")
+	if origin == "" || !c.isOriginAllowed(origin) {
+		return
+	}
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	if !slices.Contains(c.methods, method) {
+		return
+	}
+	requested := parseHeaderList(
+		r.Header.Get("Access-Control-Request-Headers"),
+	)
+	if !c.areHeadersAllowed(requested) {
+		return
+	}
+	c.setOrigin(header, origin)
+	header.Set("Access-Control-Allow-Methods", method)
+	if len(requested) > 0 {
+		header.Set(
+			"Access-Control-Allow-Headers", strings.Join(requested, ", "),
+		)
+	}
+	if c.maxAge != "" {
+		header.Set("Access-Control-Max-Age", c.maxAge)
+	}
+}
+
+// handleSimple sets the headers for a non-preflight request.
+func (c *cors) handleSimple(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" || !c.isOriginAllowed(origin) {
+		return
+	}
+	c.setOrigin(header, origin)
+	if c.exposedHeaders != "" {
+		header.Set("Access-Control-Expose-Headers", c.exposedHeaders)
+	}
+}
+
+// setOrigin sets the allow-origin and allow-credentials headers. Credentials
+// are only allowed for listed origins, never for origins matched by "*".
+func (c *cors) setOrigin(header http.Header, origin string) {
+	credentials := c.allowCredentials && c.isOriginListed(origin)
+	if c.allowAllOrigins && !credentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+	if credentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// isOriginAllowed reports whether the origin is allowed.
+func (c *cors) isOriginAllowed(origin string) bool {
+	return c.allowAllOrigins || c.isOriginListed(origin)
+}
+
+// isOriginListed reports whether the origin is matched by an exact, wildcard
+// or regex origin, ignoring "*".
+func (c *cors) isOriginListed(origin string) bool {
+	lower := strings.ToLower(origin)
+	if slices.Contains(c.origins, lower) {
+		return true
+	}
+	for _, w := range c.wildcardOrigins {
+		if len(lower) > len(w[0])+len(w[1]) &&
+			strings.HasPrefix(lower, w[0]) && strings.HasSuffix(lower, w[1]) {
+			return true
+		}
+	}
+	for _, pattern := range c.originPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// areHeadersAllowed reports whether all requested headers are allowed.
+func (c *cors) areHeadersAllowed(requested []string) bool {
+	if c.allowAllHeaders {
+		return true
+	}
+	for _, header := range requested {
+		if !slices.Contains(c.headers, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseHeaderList splits a comma-separated header list into canonical header
+// names.
+func parseHeaderList(value string) []string {
+	var headers []string
+	for _, header := range strings.Split(value, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" {
+			headers = append(headers, http.CanonicalHeaderKey(header))
+		}
+	}
+	return headers
+}
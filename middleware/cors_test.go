@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// okHandler returns a handler that records that it was called and writes
+// "ok".
+func okHandler(called *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if called != nil {
+			*called = true
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+// CORSTestSuite is a suite of tests for the CORS middleware.
+type CORSTestSuite struct {
+	suite.Suite
+}
+
+// TestCORSTestSuite runs the test suite.
+func TestCORSTestSuite(t *testing.T) {
+	suite.Run(t, new(CORSTestSuite))
+}
+
+// serve runs the request through a CORS middleware and returns the recorder
+// and whether the next handler was called.
+func (s *CORSTestSuite) serve(
+	opts CORSOptions, r *http.Request,
+) (*httptest.ResponseRecorder, bool) {
+	called := false
+	rec := httptest.NewRecorder()
+	CORS(opts)(okHandler(&called)).ServeHTTP(rec, r)
+	return rec, called
+}
+
+// Test_Preflight tests that an allowed preflight request is answered with 204
+// and the CORS headers, without calling the next handler.
+func (s *CORSTestSuite) Test_Preflight() {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	r.Header.Set("Access-Control-Request-Headers", "content-type, x-token")
+	rec, called := s.serve(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodPut},
+		AllowedHeaders:   []string{"Content-Type", "X-Token"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}, r)
+
+	s.False(called)
+	s.Equal(http.StatusNoContent, rec.Code)
+	h := rec.Header()
+	s.Equal("https://app.example.com", h.Get("Access-Control-Allow-Origin"))
+	s.Equal(http.MethodPut, h.Get("Access-Control-Allow-Methods"))
+	s.Equal("Content-Type, X-Token", h.Get("Access-Control-Allow-Headers"))
+	s.Equal("true", h.Get("Access-Control-Allow-Credentials"))
+	s.Equal("600", h.Get("Access-Control-Max-Age"))
+	s.Contains(h.Values("Vary"), "Origin")
+}
+
+// Test_Preflight_Rejected tests that a preflight request with a disallowed
+// origin, method or header gets no CORS headers.
+func (s *CORSTestSuite) Test_Preflight_Rejected() {
+	opts := CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+	}{
+		{"origin", "https://evil.com", http.MethodGet, ""},
+		{"method", "https://app.example.com", http.MethodDelete, ""},
+		{"header", "https://app.example.com", http.MethodGet, "X-Other"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			r := httptest.NewRequest(http.MethodOptions, "/", nil)
+			r.Header.Set("Origin", tt.origin)
+			r.Header.Set("Access-Control-Request-Method", tt.method)
+			if tt.headers != "" {
+				r.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			rec, called := s.serve(opts, r)
+			s.False(called)
+			s.Equal(http.StatusNoContent, rec.Code)
+			s.Empty(rec.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
+
+// Test_Simple tests that a simple request passes through with the CORS
+// headers set.
+func (s *CORSTestSuite) Test_Simple() {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Origin", "https://any.com")
+	rec, called := s.serve(CORSOptions{
+		AllowedOrigins: []string{"*"},
+		ExposedHeaders: []string{"X-Request-ID"},
+	}, r)
+
+	s.True(called)
+	s.Equal("ok", rec.Body.String())
+	s.Equal("*", rec.Header().Get("Access-Control-Allow-Origin"))
+	s.Equal("X-Request-ID", rec.Header().Get("Access-Control-Expose-Headers"))
+	s.Contains(rec.Header().Values("Vary"), "Origin")
+}
+
+// Test_Simple_CredentialsEchoOrigin tests that a listed origin is echoed
+// back with credentials when credentials are allowed.
+func (s *CORSTestSuite) Test_Simple_CredentialsEchoOrigin() {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	rec, _ := s.serve(CORSOptions{
+		AllowedOrigins:   []string{"*", "https://app.example.com"},
+		AllowCredentials: true,
+	}, r)
+	s.Equal(
+		"https://app.example.com",
+		rec.Header().Get("Access-Control-Allow-Origin"),
+	)
+	s.Equal("true", rec.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+// Test_Credentials_WildcardNotReflected tests that origins only allowed by
+// "*" are not granted credentials, for simple and preflight requests.
+func (s *CORSTestSuite) Test_Credentials_WildcardNotReflected() {
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	}
+	simple := httptest.NewRequest(http.MethodGet, "/", nil)
+	preflight := httptest.NewRequest(http.MethodOptions, "/", nil)
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	for _, r := range []*http.Request{simple, preflight} {
+		r.Header.Set("Origin", "https://evil.com")
+		rec, _ := s.serve(opts, r)
+		s.Equal("*", rec.Header().Get("Access-Control-Allow-Origin"))
+		s.Empty(rec.Header().Get("Access-Control-Allow-Credentials"))
+	}
+}
+
+// Test_Simple_OriginMatching tests wildcard, regex and disallowed origins.
+func (s *CORSTestSuite) Test_Simple_OriginMatching() {
+	opts := CORSOptions{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedOriginPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`^http://localhost:\d+$`),
+		},
+	}
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://api.example.com", true},
+		{"https://example.com", false},
+		{"http://localhost:3000", true},
+		{"https://evil.com", false},
+	}
+	for _, tt := range tests {
+		s.Run(tt.origin, func() {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Origin", tt.origin)
+			rec, called := s.serve(opts, r)
+			s.True(called)
+			allowOrigin := rec.Header().Get("Access-Control-Allow-Origin")
+			if tt.allowed {
+				s.Equal(tt.origin, allowOrigin)
+			} else {
+				s.Empty(allowOrigin)
+			}
+		})
+	}
+}
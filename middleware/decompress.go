@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// DefaultDecompressMaxSize is the decompressed body size limit in bytes used
+// when the given limit is not positive.
+const DefaultDecompressMaxSize int64 = 10 << 20
+
+// DecompressRequest returns a middleware that decompresses request bodies
+// sent with a gzip or deflate Content-Encoding, so handlers read the plain
+// body. The decompressed body is limited to maxSize bytes to guard against
+// decompression bombs; if the handler reads past the limit without writing
+// a response, 413 is written once the handler returns. Bodies with other
+// encodings are rejected with 415 and malformed compressed bodies with 400.
+//
+// Parameters:
+//   - maxSize: The maximum decompressed body size in bytes.
+//
+// Returns:
+//   - types.Middleware: The request decompression middleware.
+func DecompressRequest(maxSize int64) types.Middleware {
+	if maxSize <= 0 {
+		maxSize = DefaultDecompressMaxSize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := strings.ToLower(
+				strings.TrimSpace(r.Header.Get("Content-Encoding")),
+			)
+			if encoding == "" || encoding == "identity" || r.Body == nil ||
+				r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			decompressed, err := decompressBody(encoding, r.Body)
+			if errors.Is(err, errUnsupportedEncoding) {
+				http.Error(
+					w,
+					http.StatusText(http.StatusUnsupportedMediaType),
+					http.StatusUnsupportedMediaType,
+				)
+				return
+			}
+			if err != nil {
+				http.Error(
+					w,
+					http.StatusText(http.StatusBadRequest),
+					http.StatusBadRequest,
+				)
+				return
+			}
+			mw := &maxBodyWriter{ResponseWriter: w}
+			body := &maxBodyReader{
+				ReadCloser: http.MaxBytesReader(w, decompressed, maxSize),
+			}
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			r.Body = body
+			next.ServeHTTP(mw, r)
+			if body.exceeded && !mw.wroteHeader {
+				tooLarge(w)
+			}
+		})
+	}
+}
+
+// errUnsupportedEncoding is returned by decompressBody for encodings it
+// can not decode.
+var errUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// decompressBody wraps the body in a reader for the content encoding.
+func decompressBody(
+	encoding string, body io.ReadCloser,
+) (io.ReadCloser, error) {
+	switch encoding {
+	case encodingGzip, "x-gzip":
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &decompressReader{Reader: reader, body: body}, nil
+	case encodingDeflate:
+		reader, err := zlib.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &decompressReader{Reader: reader, body: body}, nil
+	default:
+		return nil, errUnsupportedEncoding
+	}
+}
+
+// decompressReader reads the decompressed body and closes both the
+// decompressor and the original body.
+type decompressReader struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+// Close closes the decompressor and the original body.
+//
+// Returns:
+//   - error: An error if closing fails.
+func (d *decompressReader) Close() error {
+	if closer, ok := d.Reader.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			_ = d.body.Close()
+			return err
+		}
+	}
+	return d.body.Close()
+}
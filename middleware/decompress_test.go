@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// DecompressRequestTestSuite is a suite of tests for the request
+// decompression middleware.
+type DecompressRequestTestSuite struct {
+	suite.Suite
+}
+
+// TestDecompressRequestTestSuite runs the test suite.
+func TestDecompressRequestTestSuite(t *testing.T) {
+	suite.Run(t, new(DecompressRequestTestSuite))
+}
+
+// gzipBody returns the gzip compressed data.
+func (s *DecompressRequestTestSuite) gzipBody(data string) *bytes.Buffer {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write([]byte(data))
+	s.Require().NoError(err)
+	s.Require().NoError(gw.Close())
+	return &buf
+}
+
+// Test_Gzip tests that gzip bodies are decompressed for the handler.
+func (s *DecompressRequestTestSuite) Test_Gzip() {
+	called := false
+	r := httptest.NewRequest(http.MethodPost, "/", s.gzipBody("hello"))
+	r.Header.Set("Content-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	DecompressRequest(0)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.True(called)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("hello", rec.Body.String())
+	s.Empty(r.Header.Get("Content-Encoding"))
+}
+
+// Test_Deflate tests that zlib wrapped deflate bodies are decompressed for
+// the handler.
+func (s *DecompressRequestTestSuite) Test_Deflate() {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	_, err := zw.Write([]byte("hello"))
+	s.Require().NoError(err)
+	s.Require().NoError(zw.Close())
+	called := false
+	r := httptest.NewRequest(http.MethodPost, "/", &buf)
+	r.Header.Set("Content-Encoding", "deflate")
+	rec := httptest.NewRecorder()
+	DecompressRequest(0)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.True(called)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("hello", rec.Body.String())
+}
+
+// Test_Identity tests that bodies without an encoding are passed through.
+func (s *DecompressRequestTestSuite) Test_Identity() {
+	called := false
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hi"))
+	rec := httptest.NewRecorder()
+	DecompressRequest(0)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.True(called)
+	s.Equal("hi", rec.Body.String())
+}
+
+// Test_SizeLimit tests that a body decompressing past the limit is
+// rejected with 413.
+func (s *DecompressRequestTestSuite) Test_SizeLimit() {
+	called := false
+	body := s.gzipBody(strings.Repeat("a", 1000))
+	s.Less(body.Len(), 100)
+	r := httptest.NewRequest(http.MethodPost, "/", body)
+	r.Header.Set("Content-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	DecompressRequest(100)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.True(called)
+	s.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+// Test_Unsupported tests that unsupported encodings are rejected with 415.
+func (s *DecompressRequestTestSuite) Test_Unsupported() {
+	called := false
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x"))
+	r.Header.Set("Content-Encoding", "br")
+	rec := httptest.NewRecorder()
+	DecompressRequest(0)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.False(called)
+	s.Equal(http.StatusUnsupportedMediaType, rec.Code)
+}
+
+// Test_Malformed tests that malformed gzip and deflate bodies are rejected
+// with 400.
+func (s *DecompressRequestTestSuite) Test_Malformed() {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		called := false
+		r := httptest.NewRequest(
+			http.MethodPost, "/", strings.NewReader("not compressed"),
+		)
+		r.Header.Set("Content-Encoding", encoding)
+		rec := httptest.NewRecorder()
+		DecompressRequest(0)(readingHandler(&called)).ServeHTTP(rec, r)
+		s.False(called, encoding)
+		s.Equal(http.StatusBadRequest, rec.Code, encoding)
+	}
+}
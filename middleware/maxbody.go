@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// DefaultMaxBodySize is the body size limit in bytes used when the given
+// limit is not positive.
+const DefaultMaxBodySize int64 = 1 << 20
+
+// MaxBodySize returns a middleware that limits the size of request bodies.
+// Requests with a Content-Length above the limit are rejected with 413
+// before the handler runs. Other bodies are wrapped with http.MaxBytesReader,
+// and if the handler reads past the limit without writing a response, 413 is
+// written once the handler returns.
+//
+// To override the limit for an endpoint, replace the wrapper in the
+// endpoint's stack rather than nesting a second one, since nested limits can
+// only lower the limit.
+//
+// Parameters:
+//   - n: The maximum body size in bytes.
+//
+// Returns:
+//   - types.Middleware: The body size limiting middleware.
+func MaxBodySize(n int64) types.Middleware {
+	if n <= 0 {
+		n = DefaultMaxBodySize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				tooLarge(w)
+				return
+			}
+			mw := &maxBodyWriter{ResponseWriter: w}
+			body := &maxBodyReader{
+				ReadCloser: http.MaxBytesReader(w, r.Body, n),
+			}
+			r.Body = body
+			next.ServeHTTP(mw, r)
+			if body.exceeded && !mw.wroteHeader {
+				tooLarge(w)
+			}
+		})
+	}
+}
+
+// maxBodyReader records whether the body limit was exceeded.
+type maxBodyReader struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+// Read reads from the limited body.
+//
+// Parameters:
+//   - p: The buffer to read into.
+//
+// Returns:
+//   - int: The number of bytes read.
+//   - error: An *http.MaxBytesError if the limit was exceeded.
+func (b *maxBodyReader) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// maxBodyWriter records whether a response has been started.
+type maxBodyWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader records and writes the status code.
+//
+// Parameters:
+//   - status: The status code.
+func (w *maxBodyWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records and writes the data.
+//
+// Parameters:
+//   - p: The data to write.
+//
+// Returns:
+//   - int: The number of bytes written.
+//   - error: An error if writing fails.
+func (w *maxBodyWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+//
+// Returns:
+//   - http.ResponseWriter: The underlying writer.
+func (w *maxBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// tooLarge writes a 413 response.
+func tooLarge(w http.ResponseWriter) {
+	http.Error(
+		w,
+		http.StatusText(http.StatusRequestEntityTooLarge),
+		http.StatusRequestEntityTooLarge,
+	)
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// MaxBodySizeTestSuite is a suite of tests for the body size middleware.
+type MaxBodySizeTestSuite struct {
+	suite.Suite
+}
+
+// TestMaxBodySizeTestSuite runs the test suite.
+func TestMaxBodySizeTestSuite(t *testing.T) {
+	suite.Run(t, new(MaxBodySizeTestSuite))
+}
+
+// readingHandler reads the whole body and echoes it unless reading fails.
+func readingHandler(called *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		_, _ = w.Write(body)
+	})
+}
+
+// Test_ContentLength tests that an oversized Content-Length is rejected
+// before the handler runs.
+func (s *MaxBodySizeTestSuite) Test_ContentLength() {
+	called := false
+	r := httptest.NewRequest(
+		http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 11)),
+	)
+	rec := httptest.NewRecorder()
+	MaxBodySize(10)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.False(called)
+	s.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+// Test_Streamed tests that a body without Content-Length is rejected once
+// the handler reads past the limit.
+func (s *MaxBodySizeTestSuite) Test_Streamed() {
+	called := false
+	r := httptest.NewRequest(
+		http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 11)),
+	)
+	r.ContentLength = -1
+	rec := httptest.NewRecorder()
+	MaxBodySize(10)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.True(called)
+	s.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+// Test_WithinLimit tests that a body within the limit is passed through.
+func (s *MaxBodySizeTestSuite) Test_WithinLimit() {
+	called := false
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+	rec := httptest.NewRecorder()
+	MaxBodySize(10)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.True(called)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("hello", rec.Body.String())
+}
+
+// Test_Default tests that a non-positive limit uses the default.
+func (s *MaxBodySizeTestSuite) Test_Default() {
+	called := false
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.ContentLength = DefaultMaxBodySize + 1
+	rec := httptest.NewRecorder()
+	MaxBodySize(0)(readingHandler(&called)).ServeHTTP(rec, r)
+	s.False(called)
+	s.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// DefaultRateLimitIdleTimeout is how long an unused per-key bucket is kept
+// when no idle timeout is configured.
+const DefaultRateLimitIdleTimeout = 10 * time.Minute
+
+// RateLimitOptions configures the rate limiting middleware. A limiter with a
+// non-positive rate is disabled.
+type RateLimitOptions struct {
+	// Rate is the number of requests per second allowed for each key.
+	Rate float64
+	// Burst is the maximum number of requests allowed at once for each key.
+	// Defaults to 1.
+	Burst int
+	// KeyFn derives the key of a request. Defaults to ClientIP.
+	KeyFn func(r *http.Request) string
+	// GlobalRate is the number of requests per second allowed across all
+	// keys.
+	GlobalRate float64
+	// GlobalBurst is the maximum number of requests allowed at once across
+	// all keys. Defaults to 1.
+	GlobalBurst int
+	// IdleTimeout is the minimum time an unused per-key bucket is kept. It is
+	// removed before twice this time has passed. Defaults to
+	// DefaultRateLimitIdleTimeout.
+	IdleTimeout time.Duration
+	// Clock returns the current time. Defaults to time.Now.
+	Clock func() time.Time
+}
+
+// tokenBucket is a token bucket refilled at a constant rate.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accrued since the last refill, up to burst.
+func (b *tokenBucket) refill(now time.Time, rate float64, burst int) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+		b.last = now
+	}
+}
+
+// wait returns how long until a token is available, or 0 if one is.
+func (b *tokenBucket) wait(rate float64) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// rateLimiter holds the global bucket and the per-key buckets. Per-key
+// buckets are kept in two generations: buckets used since the last rotation
+// and buckets used in the generation before it, which are dropped on the
+// next rotation.
+type rateLimiter struct {
+	opts       RateLimitOptions
+	mu         sync.Mutex
+	global     *tokenBucket
+	buckets    map[string]*tokenBucket
+	previous   map[string]*tokenBucket
+	lastRotate time.Time
+}
+
+// RateLimit returns a middleware that limits requests with token buckets. A
+// global bucket is shared by all requests and a per-key bucket is kept for
+// each key. A request consumes a token from both buckets only if both have
+// one; otherwise it is rejected with 429 and a Retry-After header. Per-key
+// buckets are dropped after being idle for one to two idle timeouts.
+//
+// Parameters:
+//   - opts: The rate limit options.
+//
+// Returns:
+//   - types.Middleware: The rate limiting middleware.
+func RateLimit(opts RateLimitOptions) types.Middleware {
+	l := newRateLimiter(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ok, wait := l.allow(r); !ok {
+				seconds := int(math.Ceil(wait.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				http.Error(
+					w,
+					http.StatusText(http.StatusTooManyRequests),
+					http.StatusTooManyRequests,
+				)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the host part of the request's remote address.
+//
+// Parameters:
+//   - r: The request.
+//
+// Returns:
+//   - string: The client IP.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// newRateLimiter applies the option defaults and creates a rate limiter.
+func newRateLimiter(opts RateLimitOptions) *rateLimiter {
+	if opts.Burst <= 0 {
+		opts.Burst = 1
+	}
+	if opts.GlobalBurst <= 0 {
+		opts.GlobalBurst = 1
+	}
+	if opts.KeyFn == nil {
+		opts.KeyFn = ClientIP
+	}
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = DefaultRateLimitIdleTimeout
+	}
+	if opts.Clock == nil {
+		opts.Clock = time.Now
+	}
+	now := opts.Clock()
+	l := &rateLimiter{
+		opts:       opts,
+		buckets:    map[string]*tokenBucket{},
+		lastRotate: now,
+	}
+	if opts.GlobalRate > 0 {
+		l.global = &tokenBucket{tokens: float64(opts.GlobalBurst), last: now}
+	}
+	return l
+}
+
+// allow reports whether the request is allowed. Otherwise it returns how long
+// until the request would be allowed. Tokens are only consumed when both the
+// global and the per-key bucket have one, so a request rejected by one
+// limiter does not use up the other.
+func (l *rateLimiter) allow(r *http.Request) (bool, time.Duration) {
+	key := ""
+	if l.opts.Rate > 0 {
+		key = l.opts.KeyFn(r)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.opts.Clock()
+	l.rotate(now)
+	var wait time.Duration
+	if l.global != nil {
+		l.global.refill(now, l.opts.GlobalRate, l.opts.GlobalBurst)
+		wait = l.global.wait(l.opts.GlobalRate)
+	}
+	var bucket *tokenBucket
+	if l.opts.Rate > 0 {
+		bucket = l.bucket(key, now)
+		bucket.refill(now, l.opts.Rate, l.opts.Burst)
+		wait = max(wait, bucket.wait(l.opts.Rate))
+	}
+	if wait > 0 {
+		return false, wait
+	}
+	if l.global != nil {
+		l.global.tokens--
+	}
+	if bucket != nil {
+		bucket.tokens--
+	}
+	return true, 0
+}
+
+// bucket returns the bucket of the key, moving it from the previous
+// generation or creating a full one if needed.
+func (l *rateLimiter) bucket(key string, now time.Time) *tokenBucket {
+	if bucket, found := l.buckets[key]; found {
+		return bucket
+	}
+	bucket, found := l.previous[key]
+	if found {
+		delete(l.previous, key)
+	} else {
+		bucket = &tokenBucket{tokens: float64(l.opts.Burst), last: now}
+	}
+	l.buckets[key] = bucket
+	return bucket
+}
+
+// rotate starts a new bucket generation once per idle timeout, dropping the
+// buckets that were not used during the previous generation. Rotating only
+// swaps maps, so the cost under the lock does not grow with the number of
+// keys.
+func (l *rateLimiter) rotate(now time.Time) {
+	elapsed := now.Sub(l.lastRotate)
+	if elapsed < l.opts.IdleTimeout {
+		return
+	}
+	l.previous = l.buckets
+	if elapsed >= 2*l.opts.IdleTimeout {
+		l.previous = nil
+	}
+	l.buckets = map[string]*tokenBucket{}
+	l.lastRotate = now
+}
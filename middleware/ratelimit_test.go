@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// RateLimitTestSuite is a suite of tests for the rate limiting middleware.
+type RateLimitTestSuite struct {
+	suite.Suite
+	clock *fakeClock
+}
+
+// TestRateLimitTestSuite runs the test suite.
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
+
+// SetupTest creates a new clock.
+func (s *RateLimitTestSuite) SetupTest() {
+	s.clock = &fakeClock{now: time.Unix(1000, 0)}
+}
+
+// do sends a request from remoteAddr and returns the recorder.
+func (s *RateLimitTestSuite) do(
+	handler http.Handler, remoteAddr string,
+) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec
+}
+
+// Test_BurstAndRefill tests that a key can burst, is then rejected with
+// Retry-After, and is allowed again after the bucket refills.
+func (s *RateLimitTestSuite) Test_BurstAndRefill() {
+	handler := RateLimit(RateLimitOptions{
+		Rate:  0.5,
+		Burst: 2,
+		Clock: s.clock.Now,
+	})(okHandler(nil))
+
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.1:1").Code)
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.1:2").Code)
+	rec := s.do(handler, "10.0.0.1:3")
+	s.Equal(http.StatusTooManyRequests, rec.Code)
+	s.Equal("2", rec.Header().Get("Retry-After"))
+
+	// Another key has its own bucket.
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.2:1").Code)
+
+	s.clock.Advance(2 * time.Second)
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.1:4").Code)
+	s.Equal(http.StatusTooManyRequests, s.do(handler, "10.0.0.1:5").Code)
+}
+
+// Test_Global tests that the global limiter applies across keys.
+func (s *RateLimitTestSuite) Test_Global() {
+	handler := RateLimit(RateLimitOptions{
+		GlobalRate:  1,
+		GlobalBurst: 1,
+		Clock:       s.clock.Now,
+	})(okHandler(nil))
+
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.1:1").Code)
+	s.Equal(http.StatusTooManyRequests, s.do(handler, "10.0.0.2:1").Code)
+	s.clock.Advance(time.Second)
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.2:1").Code)
+}
+
+// Test_RejectedDoesNotConsume tests that a request rejected by one limiter
+// does not take a token from the other.
+func (s *RateLimitTestSuite) Test_RejectedDoesNotConsume() {
+	handler := RateLimit(RateLimitOptions{
+		Rate:        1,
+		Burst:       1,
+		GlobalRate:  1,
+		GlobalBurst: 2,
+		Clock:       s.clock.Now,
+	})(okHandler(nil))
+
+	// The per-key rejection leaves the global token for another key.
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.1:1").Code)
+	s.Equal(http.StatusTooManyRequests, s.do(handler, "10.0.0.1:2").Code)
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.2:1").Code)
+
+	// The global rejection leaves the per-key token of a new key.
+	s.Equal(http.StatusTooManyRequests, s.do(handler, "10.0.0.3:1").Code)
+	s.clock.Advance(time.Second)
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.3:2").Code)
+	s.Equal(http.StatusTooManyRequests, s.do(handler, "10.0.0.3:3").Code)
+}
+
+// Test_KeyFn tests that a custom key function is used.
+func (s *RateLimitTestSuite) Test_KeyFn() {
+	handler := RateLimit(RateLimitOptions{
+		Rate:  1,
+		KeyFn: func(r *http.Request) string { return "shared" },
+		Clock: s.clock.Now,
+	})(okHandler(nil))
+
+	s.Equal(http.StatusOK, s.do(handler, "10.0.0.1:1").Code)
+	s.Equal(http.StatusTooManyRequests, s.do(handler, "10.0.0.2:1").Code)
+}
+
+// Test_Sweep tests that idle buckets are removed.
+func (s *RateLimitTestSuite) Test_Sweep() {
+	l := newRateLimiter(RateLimitOptions{
+		Rate:        1,
+		IdleTimeout: time.Minute,
+		Clock:       s.clock.Now,
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1"
+	l.allow(r)
+	s.Len(l.buckets, 1)
+
+	s.clock.Advance(2 * time.Minute)
+	r.RemoteAddr = "10.0.0.2:1"
+	l.allow(r)
+	s.Len(l.buckets, 1)
+	s.Contains(l.buckets, "10.0.0.2")
+	s.Empty(l.previous)
+
+	// A bucket used in the previous generation is kept and moved back.
+	s.clock.Advance(time.Minute)
+	r.RemoteAddr = "10.0.0.3:1"
+	l.allow(r)
+	s.Contains(l.previous, "10.0.0.2")
+	r.RemoteAddr = "10.0.0.2:1"
+	l.allow(r)
+	s.Contains(l.buckets, "10.0.0.2")
+	s.NotContains(l.previous, "10.0.0.2")
+}
+
+// Test_ClientIP tests that the port is stripped from the remote address.
+func (s *RateLimitTestSuite) Test_ClientIP() {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "[::1]:8080"
+	s.Equal("::1", ClientIP(r))
+	r.RemoteAddr = "no-port"
+	s.Equal("no-port", ClientIP(r))
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+	"github.com/pureapi/pureapi-core/util"
+)
+
+// DefaultRequestIDHeader is the header used for request IDs when none is
+// configured.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of an incoming request ID.
+const maxRequestIDLength = 128
+
+// contextKey is the type of the context keys of the package.
+type contextKey string
+
+// RequestIDOptions configures the request ID middleware.
+type RequestIDOptions struct {
+	// Header is the request and response header carrying the ID. Defaults to
+	// DefaultRequestIDHeader.
+	Header string
+	// Generator returns a new ID when the request has none. Defaults to
+	// NewUUID.
+	Generator func() string
+}
+
+// RequestID returns a middleware that reads the request ID from the incoming
+// header, or generates one if it is missing or invalid. The ID is stored in
+// the request context with util.WithRequestID, which also adds it to the
+// context log fields, and echoed in the response header.
+//
+// Parameters:
+//   - opts: The request ID options.
+//
+// Returns:
+//   - types.Middleware: The request ID middleware.
+func RequestID(opts RequestIDOptions) types.Middleware {
+	header := opts.Header
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	generator := opts.Generator
+	if generator == nil {
+		generator = NewUUID
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(header)
+			if !isValidRequestID(id) {
+				id = generator()
+			}
+			w.Header().Set(header, id)
+			ctx := util.WithRequestID(r.Context(), id)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID stored in the context. It is
+// the same as util.RequestIDFromContext.
+//
+// Parameters:
+//   - ctx: The context to read from.
+//
+// Returns:
+//   - string: The request ID, or an empty string if none is set.
+func RequestIDFromContext(ctx context.Context) string {
+	return util.RequestIDFromContext(ctx)
+}
+
+// NewUUID returns a random version 4 UUID.
+//
+// Returns:
+//   - string: The UUID.
+func NewUUID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error.
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// isValidRequestID reports whether an incoming ID is non-empty, not too long
+// and consists of printable ASCII characters only.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
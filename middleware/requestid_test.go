@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/util"
+	"github.com/stretchr/testify/suite"
+)
+
+// uuidPattern matches a version 4 UUID.
+var uuidPattern = regexp.MustCompile(
+	`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+)
+
+// RequestIDTestSuite is a suite of tests for the request ID middleware.
+type RequestIDTestSuite struct {
+	suite.Suite
+}
+
+// TestRequestIDTestSuite runs the test suite.
+func TestRequestIDTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestIDTestSuite))
+}
+
+// serve runs the request through a request ID middleware and returns the
+// recorder and the ID seen by the next handler.
+func (s *RequestIDTestSuite) serve(
+	opts RequestIDOptions, r *http.Request,
+) (*httptest.ResponseRecorder, string) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	})
+	rec := httptest.NewRecorder()
+	RequestID(opts)(next).ServeHTTP(rec, r)
+	return rec, seen
+}
+
+// Test_Incoming tests that an incoming ID is propagated and echoed.
+func (s *RequestIDTestSuite) Test_Incoming() {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(DefaultRequestIDHeader, "abc-123")
+	rec, seen := s.serve(RequestIDOptions{}, r)
+	s.Equal("abc-123", seen)
+	s.Equal("abc-123", rec.Header().Get(DefaultRequestIDHeader))
+}
+
+// Test_LogFields tests that the ID is added to the context log fields.
+func (s *RequestIDTestSuite) Test_LogFields() {
+	var fields []any
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = util.LogFieldsFromContext(r.Context())
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(DefaultRequestIDHeader, "abc-123")
+	RequestID(RequestIDOptions{})(next).ServeHTTP(httptest.NewRecorder(), r)
+	s.Equal([]any{"request_id", "abc-123"}, fields)
+}
+
+// Test_Generated tests that a UUID is generated when the header is missing or
+// invalid.
+func (s *RequestIDTestSuite) Test_Generated() {
+	for _, incoming := range []string{"", "bad id", strings.Repeat("a", 129)} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(DefaultRequestIDHeader, incoming)
+		rec, seen := s.serve(RequestIDOptions{}, r)
+		s.Regexp(uuidPattern, seen)
+		s.Equal(seen, rec.Header().Get(DefaultRequestIDHeader))
+	}
+}
+
+// Test_CustomOptions tests a custom header and generator.
+func (s *RequestIDTestSuite) Test_CustomOptions() {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec, seen := s.serve(RequestIDOptions{
+		Header:    "X-Trace",
+		Generator: func() string { return "fixed" },
+	}, r)
+	s.Equal("fixed", seen)
+	s.Equal("fixed", rec.Header().Get("X-Trace"))
+	s.Empty(rec.Header().Get(DefaultRequestIDHeader))
+}
+
+// Test_FromContext_Empty tests that an empty string is returned when no ID is
+// set.
+func (s *RequestIDTestSuite) Test_FromContext_Empty() {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	s.Empty(RequestIDFromContext(r.Context()))
+}
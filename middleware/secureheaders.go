@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// HeaderDisabled can be set as the value of a SecureHeadersOptions field to
+// omit the header.
+const HeaderDisabled = "-"
+
+// Default security header values.
+const (
+	DefaultContentTypeOptions      = "nosniff"
+	DefaultFrameOptions            = "DENY"
+	DefaultReferrerPolicy          = "strict-origin-when-cross-origin"
+	DefaultStrictTransportSecurity = "max-age=63072000; includeSubDomains"
+)
+
+// SecureHeadersOptions configures the security headers middleware. Empty
+// fields use the defaults and HeaderDisabled omits the header.
+type SecureHeadersOptions struct {
+	// ContentTypeOptions is the X-Content-Type-Options value.
+	ContentTypeOptions string
+	// FrameOptions is the X-Frame-Options value.
+	FrameOptions string
+	// ReferrerPolicy is the Referrer-Policy value.
+	ReferrerPolicy string
+	// StrictTransportSecurity is the Strict-Transport-Security value. It is
+	// only set on TLS requests unless ForceHSTS is set.
+	StrictTransportSecurity string
+	// ForceHSTS sets Strict-Transport-Security on plain HTTP requests too,
+	// e.g. behind a TLS-terminating proxy.
+	ForceHSTS bool
+	// ContentSecurityPolicy is the Content-Security-Policy value. It has no
+	// default and is omitted when empty.
+	ContentSecurityPolicy string
+}
+
+// SecureHeaders returns a middleware that sets common security headers on
+// every response.
+//
+// Parameters:
+//   - opts: The security header options.
+//
+// Returns:
+//   - types.Middleware: The security headers middleware.
+func SecureHeaders(opts SecureHeadersOptions) types.Middleware {
+	headers := map[string]string{}
+	addHeader(headers, "X-Content-Type-Options",
+		opts.ContentTypeOptions, DefaultContentTypeOptions)
+	addHeader(headers, "X-Frame-Options",
+		opts.FrameOptions, DefaultFrameOptions)
+	addHeader(headers, "Referrer-Policy",
+		opts.ReferrerPolicy, DefaultReferrerPolicy)
+	addHeader(headers, "Content-Security-Policy",
+		opts.ContentSecurityPolicy, "")
+	hsts := map[string]string{}
+	addHeader(hsts, "Strict-Transport-Security",
+		opts.StrictTransportSecurity, DefaultStrictTransportSecurity)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			for name, value := range headers {
+				header.Set(name, value)
+			}
+			if r.TLS != nil || opts.ForceHSTS {
+				for name, value := range hsts {
+					header.Set(name, value)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// addHeader adds the header to headers with the value or its default, unless
+// it is disabled or both are empty.
+func addHeader(headers map[string]string, name, value, defaultValue string) {
+	if value == "" {
+		value = defaultValue
+	}
+	if value == "" || value == HeaderDisabled {
+		return
+	}
+	headers[name] = value
+}
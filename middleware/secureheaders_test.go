@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// SecureHeadersTestSuite is a suite of tests for the security headers
+// middleware.
+type SecureHeadersTestSuite struct {
+	suite.Suite
+}
+
+// TestSecureHeadersTestSuite runs the test suite.
+func TestSecureHeadersTestSuite(t *testing.T) {
+	suite.Run(t, new(SecureHeadersTestSuite))
+}
+
+// serve runs the request through a security headers middleware.
+func (s *SecureHeadersTestSuite) serve(
+	opts SecureHeadersOptions, r *http.Request,
+) http.Header {
+	rec := httptest.NewRecorder()
+	SecureHeaders(opts)(okHandler(nil)).ServeHTTP(rec, r)
+	return rec.Header()
+}
+
+// Test_Defaults tests the default headers on a plain HTTP request, where HSTS
+// is omitted.
+func (s *SecureHeadersTestSuite) Test_Defaults() {
+	h := s.serve(
+		SecureHeadersOptions{}, httptest.NewRequest(http.MethodGet, "/", nil),
+	)
+	s.Equal(DefaultContentTypeOptions, h.Get("X-Content-Type-Options"))
+	s.Equal(DefaultFrameOptions, h.Get("X-Frame-Options"))
+	s.Equal(DefaultReferrerPolicy, h.Get("Referrer-Policy"))
+	s.Empty(h.Get("Content-Security-Policy"))
+	s.Empty(h.Get("Strict-Transport-Security"))
+}
+
+// Test_HSTS tests that HSTS is set on TLS requests and when forced.
+func (s *SecureHeadersTestSuite) Test_HSTS() {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.TLS = &tls.ConnectionState{}
+	h := s.serve(SecureHeadersOptions{}, r)
+	s.Equal(DefaultStrictTransportSecurity, h.Get("Strict-Transport-Security"))
+
+	h = s.serve(
+		SecureHeadersOptions{
+			ForceHSTS:               true,
+			StrictTransportSecurity: "max-age=1",
+		},
+		httptest.NewRequest(http.MethodGet, "/", nil),
+	)
+	s.Equal("max-age=1", h.Get("Strict-Transport-Security"))
+}
+
+// Test_OverrideAndDisable tests that headers can be overridden and disabled.
+func (s *SecureHeadersTestSuite) Test_OverrideAndDisable() {
+	h := s.serve(SecureHeadersOptions{
+		FrameOptions:          "SAMEORIGIN",
+		ReferrerPolicy:        HeaderDisabled,
+		ContentSecurityPolicy: "default-src 'self'",
+	}, httptest.NewRequest(http.MethodGet, "/", nil))
+	s.Equal("SAMEORIGIN", h.Get("X-Frame-Options"))
+	s.NotContains(h, "Referrer-Policy")
+	s.Equal("default-src 'self'", h.Get("Content-Security-Policy"))
+}
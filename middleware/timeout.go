@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// Timeout returns a middleware that runs the handler with a request context
+// that is cancelled after d. If the handler has not finished by then, 503 is
+// written and any later writes by the handler are discarded. Database
+// operations that use the request context are cancelled along with it.
+//
+// The response is buffered until the handler finishes, so streaming
+// handlers should not be wrapped with this middleware.
+//
+// Parameters:
+//   - d: The request timeout.
+//
+// Returns:
+//   - types.Middleware: The timeout middleware.
+func Timeout(d time.Duration) types.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(
+			next, d, http.StatusText(http.StatusServiceUnavailable),
+		)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// TimeoutTestSuite is a suite of tests for the timeout middleware.
+type TimeoutTestSuite struct {
+	suite.Suite
+}
+
+// TestTimeoutTestSuite runs the test suite.
+func TestTimeoutTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeoutTestSuite))
+}
+
+// Test_TimedOut tests that a handler sleeping past the deadline gets 503, sees
+// its context cancelled and cannot write after the timeout response.
+func (s *TimeoutTestSuite) Test_TimedOut() {
+	ctxErr := make(chan error, 1)
+	writeErr := make(chan error, 1)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		ctxErr <- r.Context().Err()
+		time.Sleep(10 * time.Millisecond)
+		_, err := w.Write([]byte("late"))
+		writeErr <- err
+	})
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	Timeout(20*time.Millisecond)(next).ServeHTTP(rec, r)
+
+	s.Equal(http.StatusServiceUnavailable, rec.Code)
+	s.ErrorIs(<-ctxErr, context.DeadlineExceeded)
+	s.ErrorIs(<-writeErr, http.ErrHandlerTimeout)
+	s.NotContains(rec.Body.String(), "late")
+}
+
+// Test_InTime tests that a handler finishing in time writes its response.
+func (s *TimeoutTestSuite) Test_InTime() {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "value")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("ok"))
+	})
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	Timeout(time.Second)(next).ServeHTTP(rec, r)
+
+	s.Equal(http.StatusCreated, rec.Code)
+	s.Equal("value", rec.Header().Get("X-Test"))
+	s.Equal("ok", rec.Body.String())
+}
@@ -0,0 +1,13 @@
+package types
+
+import "context"
+
+// Claims holds the claims of a verified token.
+type Claims map[string]any
+
+// TokenVerifier verifies bearer tokens.
+type TokenVerifier interface {
+	// Verify returns the claims of the token, or an error if the token is not
+	// valid.
+	Verify(ctx context.Context, token string) (Claims, error)
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pureapi/pureapi-core/endpoint"
+	endpointtypes "github.com/pureapi/pureapi-core/endpoint/types"
+)
+
+// Default health endpoint paths.
+const (
+	DefaultLivenessPath  = "/healthz"
+	DefaultReadinessPath = "/readyz"
+)
+
+// DefaultHealthCheckTimeout is how long a readiness check may run when no
+// timeout is configured.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheck is a named readiness check, e.g. a database ping.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Timeout is how long the check may run before it counts as failing.
+	// Defaults to DefaultHealthCheckTimeout.
+	Timeout time.Duration
+}
+
+// healthResponse is the JSON body of the health endpoints.
+type healthResponse struct {
+	Status  string   `json:"status"`
+	Failing []string `json:"failing,omitempty"`
+}
+
+// HealthEndpoints returns liveness and readiness endpoints at the default
+// paths.
+//
+// Parameters:
+//   - checks: The readiness checks.
+//
+// Returns:
+//   - []endpointtypes.Endpoint: The liveness and readiness endpoints.
+//   - error: An error if a check has no Check function.
+func HealthEndpoints(
+	checks ...HealthCheck,
+) ([]endpointtypes.Endpoint, error) {
+	return HealthEndpointsAt(
+		DefaultLivenessPath, DefaultReadinessPath, checks...,
+	)
+}
+
+// HealthEndpointsAt returns liveness and readiness endpoints at the given
+// paths. The liveness endpoint always responds with 200 while the server is
+// serving. The readiness endpoint runs the checks and responds with 200 if
+// all pass, or with 503 and the names of the failing checks otherwise. Each
+// check runs under its timeout, and a check that has not returned when the
+// timeout expires counts as failing.
+//
+// Parameters:
+//   - livenessPath: The path of the liveness endpoint.
+//   - readinessPath: The path of the readiness endpoint.
+//   - checks: The readiness checks.
+//
+// Returns:
+//   - []endpointtypes.Endpoint: The liveness and readiness endpoints.
+//   - error: An error if a check has no Check function.
+func HealthEndpointsAt(
+	livenessPath string, readinessPath string, checks ...HealthCheck,
+) ([]endpointtypes.Endpoint, error) {
+	for i, check := range checks {
+		if check.Check == nil {
+			return nil, fmt.Errorf(
+				"HealthEndpointsAt: check %d (%q) has no Check function",
+				i, check.Name,
+			)
+		}
+	}
+	return []endpointtypes.Endpoint{
+		endpoint.NewEndpoint(livenessPath, http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+			},
+		),
+		endpoint.NewEndpoint(readinessPath, http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				failing := failingChecks(r.Context(), checks)
+				if len(failing) > 0 {
+					writeHealth(
+						w,
+						http.StatusServiceUnavailable,
+						healthResponse{Status: "unavailable", Failing: failing},
+					)
+					return
+				}
+				writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+			},
+		),
+	}, nil
+}
+
+// failingChecks runs the checks concurrently and returns the names of the
+// failing ones in the order of the checks. The probe takes at most as long
+// as the largest check timeout.
+func failingChecks(ctx context.Context, checks []HealthCheck) []string {
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+	failing := []string{}
+	for i, err := range errs {
+		if err != nil {
+			failing = append(failing, checks[i].Name)
+		}
+	}
+	return failing
+}
+
+// runCheck runs the check under its timeout. The check runs in its own
+// goroutine so that a check ignoring its context still fails on time. A
+// panicking check fails instead of crashing the process.
+func runCheck(ctx context.Context, check HealthCheck) error {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf(
+					"health check %q panicked: %v", check.Name, r,
+				)
+			}
+		}()
+		done <- check.Check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writeHealth writes the health response as JSON.
+func writeHealth(w http.ResponseWriter, status int, res healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
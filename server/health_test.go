@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthEndpoints_Liveness(t *testing.T) {
+	failing := HealthCheck{
+		Name:  "db",
+		Check: func(ctx context.Context) error { return errors.New("down") },
+	}
+	endpoints, err := HealthEndpoints(failing)
+	require.NoError(t, err)
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", DefaultLivenessPath, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
+
+func TestHealthEndpoints_Readiness(t *testing.T) {
+	ok := HealthCheck{
+		Name:  "cache",
+		Check: func(ctx context.Context) error { return nil },
+	}
+	failing := HealthCheck{
+		Name:  "db",
+		Check: func(ctx context.Context) error { return errors.New("down") },
+	}
+
+	endpoints, err := HealthEndpoints(ok)
+	require.NoError(t, err)
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", DefaultReadinessPath, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	endpoints, err = HealthEndpointsAt("/live", "/ready", ok, failing)
+	require.NoError(t, err)
+	mux = mustSetupMux(t, NewHandler(nil), endpoints)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var res healthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Equal(t, "unavailable", res.Status)
+	assert.Equal(t, []string{"db"}, res.Failing)
+}
+
+func TestHealthEndpoints_NilCheck(t *testing.T) {
+	endpoints, err := HealthEndpoints(HealthCheck{Name: "db"})
+	assert.Nil(t, endpoints)
+	assert.EqualError(
+		t, err, `HealthEndpointsAt: check 0 ("db") has no Check function`,
+	)
+}
+
+func TestHealthEndpoints_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	stuck := HealthCheck{
+		Name: "stuck",
+		Check: func(ctx context.Context) error {
+			<-release
+			return nil
+		},
+		Timeout: 10 * time.Millisecond,
+	}
+	slow := HealthCheck{
+		Name: "slow",
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Timeout: 10 * time.Millisecond,
+	}
+	endpoints, err := HealthEndpoints(stuck, slow)
+	require.NoError(t, err)
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", DefaultReadinessPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	var res healthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Equal(t, []string{"stuck", "slow"}, res.Failing)
+}
+
+func TestHealthEndpoints_Panic(t *testing.T) {
+	panicking := HealthCheck{
+		Name:  "panics",
+		Check: func(ctx context.Context) error { panic("boom") },
+	}
+	err := runCheck(context.Background(), panicking)
+	assert.EqualError(t, err, `health check "panics" panicked: boom`)
+
+	endpoints, err := HealthEndpoints(panicking)
+	require.NoError(t, err)
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", DefaultReadinessPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
+
+func TestHealthEndpoints_Concurrent(t *testing.T) {
+	slow := func(name string) HealthCheck {
+		return HealthCheck{
+			Name: name,
+			Check: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			Timeout: 200 * time.Millisecond,
+		}
+	}
+	endpoints, err := HealthEndpoints(slow("a"), slow("b"))
+	require.NoError(t, err)
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", DefaultReadinessPath, nil))
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	var res healthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Equal(t, []string{"a", "b"}, res.Failing)
+}
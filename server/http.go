@@ -4,14 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httputil"
 	"os"
 	"os/signal"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	endpointtypes "github.com/pureapi/pureapi-core/endpoint/types"
+	"github.com/pureapi/pureapi-core/middleware"
 	servertypes "github.com/pureapi/pureapi-core/server/types"
 	"github.com/pureapi/pureapi-core/util"
 	utiltypes "github.com/pureapi/pureapi-core/util/types"
@@ -28,11 +34,23 @@ const (
 	EventShutDownStarted  utiltypes.EventType = "event_shutdown_started"
 	EventShutDown         utiltypes.EventType = "event_shutdown"
 	EventShutDownError    utiltypes.EventType = "event_shutdown_error"
+	EventDuplicateRoute   utiltypes.EventType = "event_duplicate_route"
 )
 
+// maxPanicRequestDump is the maximum size of the request dump in panic
+// events.
+const maxPanicRequestDump = 2048
+
+// redactedPanicHeaders are the headers redacted in panic request dumps.
+var redactedPanicHeaders = []string{
+	"Authorization", "Cookie", "Proxy-Authorization",
+}
+
 // DefaultHTTPServer returns the default HTTP server implementation. It sets
 // default request read and write timeouts of 10 seconds, idle timeout of 60
-// seconds, and a max header size of 64KB.
+// seconds, and a max header size of 64KB. It panics if the endpoint URLs can
+// not be registered, e.g. because two URL patterns conflict; use
+// NewHTTPServer to get the error instead.
 //
 // Parameters:
 //   - handler: HTTP server handler.
@@ -44,14 +62,40 @@ const (
 func DefaultHTTPServer(
 	handler *Handler, port int, endpoints []endpointtypes.Endpoint,
 ) *http.Server {
+	server, err := NewHTTPServer(handler, port, endpoints)
+	if err != nil {
+		panic(err)
+	}
+	return server
+}
+
+// NewHTTPServer is like DefaultHTTPServer but returns an error if the
+// endpoint URLs can not be registered, e.g. because a URL is not a valid
+// pattern or two URL patterns conflict.
+//
+// Parameters:
+//   - handler: HTTP server handler.
+//   - port: Port for the HTTP server.
+//   - endpoints: Endpoints to register.
+//
+// Returns:
+//   - *http.Server: http.Server instance.
+//   - error: An error if the endpoints can not be registered.
+func NewHTTPServer(
+	handler *Handler, port int, endpoints []endpointtypes.Endpoint,
+) (*http.Server, error) {
+	mux, err := handler.setupMux(endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("NewHTTPServer: %w", err)
+	}
 	return &http.Server{
 		Addr:           fmt.Sprintf(":%d", port),
-		Handler:        handler.setupMux(endpoints),
+		Handler:        mux,
 		ReadTimeout:    10 * time.Second, // Limits slow clients.
 		WriteTimeout:   10 * time.Second, // Ensures fast responses.
 		IdleTimeout:    60 * time.Second, // Keeps alive long enough.
 		MaxHeaderBytes: 1 << 16,          // 64KB to prevent excessive memory use.
-	}
+	}, nil
 }
 
 // StartServer sets up an HTTP server with the specified port and endpoints,
@@ -71,20 +115,101 @@ func StartServer(
 	server servertypes.HTTPServer,
 	shutdownTimeout *time.Duration,
 ) error {
-	var useShutdownTimeout time.Duration
-	if shutdownTimeout == nil {
-		useShutdownTimeout = 60 * time.Second
-	} else {
-		useShutdownTimeout = *shutdownTimeout
-	}
 	return handler.startServer(
-		make(chan os.Signal, 1), server, useShutdownTimeout,
+		make(chan os.Signal, 1),
+		server,
+		shutdownTimeoutOrDefault(shutdownTimeout),
+	)
+}
+
+// StartServerTLS is like StartServer but serves HTTPS using the certificate
+// and key files. If the server's TLS config already holds the certificates,
+// e.g. when they are loaded from a secret store, certFile and keyFile can be
+// empty.
+//
+// Parameters:
+//   - handler: HTTP server handler.
+//   - server: Server implementation to use.
+//   - certFile: Path to the certificate file.
+//   - keyFile: Path to the private key file.
+//   - shutdownTimeout: Optional shutdown timeout.
+//
+// Returns:
+//   - error: Error starting the server.
+func StartServerTLS(
+	handler *Handler,
+	server servertypes.HTTPServer,
+	certFile string,
+	keyFile string,
+	shutdownTimeout *time.Duration,
+) error {
+	return handler.startServerWith(
+		make(chan os.Signal, 1),
+		server,
+		func() error { return server.ListenAndServeTLS(certFile, keyFile) },
+		shutdownTimeoutOrDefault(shutdownTimeout),
 	)
 }
 
+// StartServerWithListener is like StartServer but serves on the given
+// listener instead of binding the server's address. This is useful for tests
+// and socket activation.
+//
+// Parameters:
+//   - handler: HTTP server handler.
+//   - server: Server implementation to use.
+//   - ln: Listener to serve on.
+//   - shutdownTimeout: Optional shutdown timeout.
+//
+// Returns:
+//   - error: Error starting the server.
+func StartServerWithListener(
+	handler *Handler,
+	server servertypes.HTTPServer,
+	ln net.Listener,
+	shutdownTimeout *time.Duration,
+) error {
+	return handler.startServerWith(
+		make(chan os.Signal, 1),
+		server,
+		func() error { return server.Serve(ln) },
+		shutdownTimeoutOrDefault(shutdownTimeout),
+	)
+}
+
+// shutdownTimeoutOrDefault returns the shutdown timeout, or 60 seconds if it
+// is nil.
+func shutdownTimeoutOrDefault(shutdownTimeout *time.Duration) time.Duration {
+	if shutdownTimeout == nil {
+		return 60 * time.Second
+	}
+	return *shutdownTimeout
+}
+
+// DefaultShutdownSignals are the OS signals that trigger a graceful shutdown
+// when none are configured.
+var DefaultShutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
 // Handler represents an HTTP server handler.
 type Handler struct {
 	emitterLogger utiltypes.EmitterLogger
+	middlewares   endpointtypes.Middlewares
+	signals       []os.Signal
+	shutdown      *shutdownTrigger
+	shutdownHooks []func(ctx context.Context)
+	preDrainDelay time.Duration
+}
+
+// shutdownTrigger is a one-shot trigger for a programmatic shutdown. It is
+// shared by copies of a Handler.
+type shutdownTrigger struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// trigger fires the trigger. Only the first call has an effect.
+func (t *shutdownTrigger) trigger() {
+	t.once.Do(func() { close(t.ch) })
 }
 
 // NewHandler creates a new HTTPServer.
@@ -106,7 +231,84 @@ func NewHandler(emitterLogger utiltypes.EmitterLogger) *Handler {
 	}
 	return &Handler{
 		emitterLogger: useEmitterLogger,
+		signals:       DefaultShutdownSignals,
+		shutdown:      &shutdownTrigger{ch: make(chan struct{})},
+	}
+}
+
+// WithSignals sets the OS signals that trigger a graceful shutdown. If no
+// signals are given, DefaultShutdownSignals are used. It returns a new
+// Handler.
+//
+// Parameters:
+//   - signals: The shutdown signals.
+//
+// Returns:
+//   - *Handler: A new Handler instance.
+func (s *Handler) WithSignals(signals ...os.Signal) *Handler {
+	new := *s
+	if len(signals) == 0 {
+		new.signals = DefaultShutdownSignals
+	} else {
+		new.signals = signals
 	}
+	return &new
+}
+
+// OnShutdown registers a hook that runs after a shutdown is triggered but
+// before the server stops accepting requests, e.g. to fail readiness checks.
+// Hooks run in registration order with a context bounded by the shutdown
+// timeout. It returns a new Handler.
+//
+// Parameters:
+//   - fn: The hook to run.
+//
+// Returns:
+//   - *Handler: A new Handler instance.
+func (s *Handler) OnShutdown(fn func(ctx context.Context)) *Handler {
+	new := *s
+	new.shutdownHooks = append(slices.Clone(s.shutdownHooks), fn)
+	return &new
+}
+
+// WithPreDrainDelay sets how long to wait after the shutdown hooks have run
+// before the server stops accepting requests. This gives load balancers time
+// to notice a failing readiness check. It returns a new Handler.
+//
+// Parameters:
+//   - delay: The pre-drain delay.
+//
+// Returns:
+//   - *Handler: A new Handler instance.
+func (s *Handler) WithPreDrainDelay(delay time.Duration) *Handler {
+	new := *s
+	new.preDrainDelay = delay
+	return &new
+}
+
+// Shutdown triggers a graceful shutdown of the running server without an OS
+// signal. The trigger is one-shot: after it fires, a server started with this
+// handler shuts down right away.
+func (s *Handler) Shutdown() {
+	s.shutdown.trigger()
+}
+
+// WithMiddlewares sets global middlewares that are applied to every URL,
+// including not found, method not allowed and OPTIONS responses. Global
+// middlewares are the outermost layer and run before the endpoint's own
+// middlewares. It returns a new Handler.
+//
+// Parameters:
+//   - middlewares: The global middlewares.
+//
+// Returns:
+//   - *Handler: A new Handler instance.
+func (s *Handler) WithMiddlewares(
+	middlewares endpointtypes.Middlewares,
+) *Handler {
+	new := *s
+	new.middlewares = middlewares
+	return &new
 }
 
 // startServer starts the HTTP server and listens for shutdown signals.
@@ -114,22 +316,44 @@ func (s *Handler) startServer(
 	stopChan chan os.Signal,
 	server servertypes.HTTPServer,
 	shutdownTimeout time.Duration,
+) error {
+	return s.startServerWith(
+		stopChan, server, server.ListenAndServe, shutdownTimeout,
+	)
+}
+
+// startServerWith starts the server with the serve function and listens for
+// shutdown signals.
+func (s *Handler) startServerWith(
+	stopChan chan os.Signal,
+	server servertypes.HTTPServer,
+	serve func() error,
+	shutdownTimeout time.Duration,
 ) error {
 	// Prepare channel for shutdown signal.
-	signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(stopChan, s.signals...)
+	defer signal.Stop(stopChan)
 	errChan := make(chan error, 1)
 
 	go func() {
-		s.listenAndServe(server, errChan, stopChan)
+		s.listenAndServe(serve, errChan, stopChan)
 	}()
 
-	// Wait for shutdown signal.
-	<-stopChan
+	// Wait for shutdown signal or a programmatic shutdown.
+	select {
+	case <-stopChan:
+	case <-s.shutdown.ch:
+	}
 
-	// Give the server some time to shut down.
 	s.emitterLogger.Info(
 		utiltypes.NewEvent(EventShutDownStarted, "Shutting down HTTP server"),
 	)
+	s.runShutdownHooks(shutdownTimeout)
+	if s.preDrainDelay > 0 {
+		time.Sleep(s.preDrainDelay)
+	}
+
+	// Give the server some time to shut down.
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
@@ -148,14 +372,27 @@ func (s *Handler) startServer(
 	return <-errChan
 }
 
-// listenAndServe listens and serves the HTTP server.
+// runShutdownHooks runs the shutdown hooks with a context bounded by the
+// shutdown timeout.
+func (s *Handler) runShutdownHooks(shutdownTimeout time.Duration) {
+	if len(s.shutdownHooks) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, hook := range s.shutdownHooks {
+		hook(ctx)
+	}
+}
+
+// listenAndServe runs the serve function of the HTTP server.
 func (s *Handler) listenAndServe(
-	server servertypes.HTTPServer, errChan chan error, stopChan chan os.Signal,
+	serve func() error, errChan chan error, stopChan chan os.Signal,
 ) {
 	s.emitterLogger.Info(
 		utiltypes.NewEvent(EventStart, "Starting HTTP server"),
 	)
-	err := server.ListenAndServe()
+	err := serve()
 	if !errors.Is(err, http.ErrServerClosed) {
 		s.emitterLogger.Error(
 			utiltypes.NewEvent(
@@ -170,14 +407,25 @@ func (s *Handler) listenAndServe(
 	}
 }
 
-// setupMux sets up the HTTP mux with the specified endpoints.
+// setupMux sets up the HTTP mux with the specified endpoints. Each URL is
+// registered as a path-only http.ServeMux pattern, so URLs can contain
+// wildcards such as "/users/{id}" that handlers read with r.PathValue. Method
+// dispatch stays in the endpoint handler, which keeps the method not allowed
+// and OPTIONS behavior and events the same for all URLs. URLs are registered
+// in sorted order, and invalid or conflicting patterns are returned as an
+// error instead of panicking.
 func (s *Handler) setupMux(
 	httpEndpoints []endpointtypes.Endpoint,
-) *http.ServeMux {
+) (*http.ServeMux, error) {
 	mux := http.NewServeMux()
-	endpoints := s.multiplexEndpoints(httpEndpoints)
+	endpoints, middlewares := s.multiplexEndpoints(httpEndpoints)
 
+	urls := make([]string, 0, len(endpoints))
 	for url := range endpoints {
+		urls = append(urls, url)
+	}
+	slices.Sort(urls)
+	for _, url := range urls {
 		methods := mapKeys(endpoints[url])
 		s.emitterLogger.Info(
 			utiltypes.NewEvent(
@@ -185,27 +433,87 @@ func (s *Handler) setupMux(
 				fmt.Sprintf("Registering URL: %s %v", url, methods),
 			).WithData(map[string]any{"path": url, "methods": methods}),
 		)
-		iterURL := url
-		mux.Handle(iterURL, s.createEndpointHandler(endpoints[iterURL]))
+		handler := s.globalHandler(
+			s.createEndpointHandler(endpoints[url], middlewares[url]),
+		)
+		if err := registerPattern(mux, url, handler); err != nil {
+			return nil, err
+		}
 	}
 
 	// Only register the not found handler if "/" is not already an endpoint.
 	if _, exists := endpoints["/"]; !exists {
-		mux.Handle("/", s.createNotFoundHandler())
+		handler := s.globalHandler(s.createNotFoundHandler())
+		if err := registerPattern(mux, "/", handler); err != nil {
+			return nil, err
+		}
 	}
 
-	return mux
+	return mux, nil
+}
+
+// registerPattern registers the handler for the pattern, returning the panic
+// of http.ServeMux.Handle for invalid or conflicting patterns as an error.
+func registerPattern(
+	mux *http.ServeMux, pattern string, handler http.Handler,
+) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("setupMux: register URL %q: %v", pattern, r)
+		}
+	}()
+	mux.Handle(pattern, handler)
+	return nil
+}
+
+// globalHandler wraps the handler with the global middlewares and recovers
+// from panics raised by them.
+func (s *Handler) globalHandler(handler http.Handler) http.Handler {
+	if s.middlewares == nil {
+		return handler
+	}
+	return s.serverPanicHandler(s.middlewares.Chain(handler))
 }
 
 // createEndpointHandler creates an HTTP handler for the specified endpoints.
+// Method not allowed responses list the registered methods in the Allow
+// header. Unless OPTIONS is registered, OPTIONS requests are answered with
+// 204 and the same Allow header. Preflight requests whose
+// Access-Control-Request-Method names a registered method run through that
+// endpoint's middlewares, so per-endpoint CORS middlewares can answer them.
 func (s *Handler) createEndpointHandler(
 	endpoints map[string]http.Handler,
+	middlewares map[string]endpointtypes.Middlewares,
 ) http.HandlerFunc {
+	methods := mapKeys(endpoints)
+	if _, ok := endpoints[http.MethodOptions]; !ok {
+		methods = append(methods, http.MethodOptions)
+	}
+	slices.Sort(methods)
+	allow := strings.Join(methods, ", ")
+	options := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	preflight := make(map[string]http.Handler, len(middlewares))
+	for method, mws := range middlewares {
+		preflight[method] = s.serverPanicHandler(mws.Chain(options))
+	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		if handler, ok := endpoints[r.Method]; ok {
 			handler.ServeHTTP(w, r)
 			return
 		}
+		if r.Method == http.MethodOptions {
+			requested := r.Header.Get("Access-Control-Request-Method")
+			if handler, ok := preflight[requested]; ok {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			options.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Allow", allow)
 		s.emitterLogger.Info(
 			utiltypes.NewEvent(
 				EventMethodNotAllowed,
@@ -239,28 +547,53 @@ func (s *Handler) createNotFoundHandler() http.HandlerFunc {
 	}
 }
 
-// multiplexEndpoints multiplexes endpoints by URL and method.
+// multiplexEndpoints multiplexes endpoint handlers and middlewares by URL and
+// method.
 func (s *Handler) multiplexEndpoints(
 	endpoints []endpointtypes.Endpoint,
-) map[string]map[string]http.Handler {
+) (
+	map[string]map[string]http.Handler,
+	map[string]map[string]endpointtypes.Middlewares,
+) {
 	multiplexed := make(map[string]map[string]http.Handler)
+	middlewares := make(map[string]map[string]endpointtypes.Middlewares)
 	for _, endpoint := range endpoints {
-		s.multiplexEndpoint(endpoint, multiplexed)
+		s.multiplexEndpoint(endpoint, multiplexed, middlewares)
 	}
-	return multiplexed
+	return multiplexed, middlewares
 }
 
-// multiplexEndpoint multiplexes an endpoint by URL and method.
+// multiplexEndpoint multiplexes an endpoint by URL and method. If an
+// endpoint is already registered for the URL and method, a warning is
+// emitted and the later endpoint replaces it.
 func (s *Handler) multiplexEndpoint(
 	endpoint endpointtypes.Endpoint,
 	multiplexed map[string]map[string]http.Handler,
+	middlewares map[string]map[string]endpointtypes.Middlewares,
 ) {
 	if multiplexed[endpoint.URL()] == nil {
 		multiplexed[endpoint.URL()] = make(map[string]http.Handler)
+		middlewares[endpoint.URL()] = make(
+			map[string]endpointtypes.Middlewares,
+		)
 	}
-	middlewares := endpoint.Middlewares()
+	if _, exists := multiplexed[endpoint.URL()][endpoint.Method()]; exists {
+		s.emitterLogger.Warn(
+			utiltypes.NewEvent(
+				EventDuplicateRoute,
+				fmt.Sprintf(
+					"Duplicate route: %s %s, the later endpoint is used",
+					endpoint.Method(), endpoint.URL(),
+				),
+			).WithData(map[string]any{
+				"path": endpoint.URL(), "method": endpoint.Method(),
+			}),
+		)
+	}
+	endpointMiddlewares := endpoint.Middlewares()
+	middlewares[endpoint.URL()][endpoint.Method()] = endpointMiddlewares
 	multiplexed[endpoint.URL()][endpoint.Method()] = s.serverPanicHandler(
-		middlewares.Chain(emptyOrCustomHandler(endpoint)),
+		endpointMiddlewares.Chain(emptyOrCustomHandler(endpoint)),
 	)
 }
 
@@ -281,20 +614,40 @@ func (s *Handler) serverPanicHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				s.panicRecovery(w, err)
+				s.panicRecovery(w, r, err)
 			}
 		}()
 		next.ServeHTTP(w, r)
 	})
 }
 
-// panicRecovery handles recovery from panics.
-func (s *Handler) panicRecovery(w http.ResponseWriter, err any) {
+// panicRecovery handles recovery from panics. The panic event data holds the
+// stack trace and a truncated dump of the request. The request ID is
+// included in
+// the event data if it is available from the request context or from the
+// response header set by the request ID middleware. The log fields of the
+// request context are included in the event data and passed to the logger
+// factory.
+func (s *Handler) panicRecovery(
+	w http.ResponseWriter, r *http.Request, err any,
+) {
+	data := map[string]any{
+		"stack":   stackTraceSlice(),
+		"request": dumpPanicRequest(r),
+	}
+	if requestID := panicRequestID(w, r); requestID != "" {
+		data["request_id"] = requestID
+	}
+	fields := util.LogFieldsFromContext(r.Context())
+	if len(fields) > 0 {
+		data["fields"] = fields
+	}
 	s.emitterLogger.Error(
 		utiltypes.NewEvent(
 			EventPanic,
 			fmt.Sprintf("Server panic: %v", err),
-		).WithData(map[string]any{"stack": stackTraceSlice()}),
+		).WithData(data),
+		fields...,
 	)
 	http.Error(
 		w,
@@ -303,6 +656,35 @@ func (s *Handler) panicRecovery(w http.ResponseWriter, err any) {
 	)
 }
 
+// dumpPanicRequest returns a dump of the request line and headers, truncated
+// to maxPanicRequestDump bytes. Credential headers are redacted and the body
+// is not included.
+func dumpPanicRequest(r *http.Request) string {
+	clone := r.Clone(r.Context())
+	clone.Body = nil
+	for _, header := range redactedPanicHeaders {
+		if clone.Header.Get(header) != "" {
+			clone.Header.Set(header, "[REDACTED]")
+		}
+	}
+	dump, err := httputil.DumpRequest(clone, false)
+	if err != nil {
+		return fmt.Sprintf("dump request: %v", err)
+	}
+	if len(dump) > maxPanicRequestDump {
+		return string(dump[:maxPanicRequestDump]) + "...(truncated)"
+	}
+	return string(dump)
+}
+
+// panicRequestID returns the request ID of a panicking request.
+func panicRequestID(w http.ResponseWriter, r *http.Request) string {
+	if requestID := middleware.RequestIDFromContext(r.Context()); requestID != "" {
+		return requestID
+	}
+	return w.Header().Get(middleware.DefaultRequestIDHeader)
+}
+
 // stackTraceSlice returns the stack trace as a slice of strings.
 func stackTraceSlice() []string {
 	var trace []string
@@ -4,14 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/pureapi/pureapi-core/endpoint"
 	"github.com/pureapi/pureapi-core/endpoint/types"
+	"github.com/pureapi/pureapi-core/middleware"
+	"github.com/pureapi/pureapi-core/util"
+	utiltypes "github.com/pureapi/pureapi-core/util/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +31,8 @@ type DummyHTTPServer struct {
 	ShutdownCalled    bool
 	ListenAndServeErr error
 	ShutdownErr       error
+	CertFile          string
+	KeyFile           string
 	listenCh          chan struct{}
 }
 
@@ -41,6 +52,16 @@ func (d *DummyHTTPServer) ListenAndServe() error {
 	return nil
 }
 
+func (d *DummyHTTPServer) ListenAndServeTLS(certFile, keyFile string) error {
+	d.CertFile = certFile
+	d.KeyFile = keyFile
+	return d.ListenAndServe()
+}
+
+func (d *DummyHTTPServer) Serve(ln net.Listener) error {
+	return d.ListenAndServe()
+}
+
 func (d *DummyHTTPServer) Shutdown(ctx context.Context) error {
 	d.ShutdownCalled = true
 	// Only close the channel if it's not already closed.
@@ -53,6 +74,16 @@ func (d *DummyHTTPServer) Shutdown(ctx context.Context) error {
 	return d.ShutdownErr
 }
 
+// mustSetupMux sets up the mux of the handler and fails the test on error.
+func mustSetupMux(
+	t *testing.T, handler *Handler, endpoints []types.Endpoint,
+) *http.ServeMux {
+	t.Helper()
+	mux, err := handler.setupMux(endpoints)
+	require.NoError(t, err)
+	return mux
+}
+
 func TestDefaultHTTPServer(t *testing.T) {
 	// Create a basic server handler.
 	handler := NewHandler(nil)
@@ -69,6 +100,37 @@ func TestDefaultHTTPServer(t *testing.T) {
 	assert.NotNil(t, server.Handler)
 }
 
+func TestSetupMux_ConflictingPatterns(t *testing.T) {
+	endpoints := []types.Endpoint{
+		endpoint.NewEndpoint("/users/{id}", "GET"),
+		endpoint.NewEndpoint("/users/{name}", "POST"),
+	}
+	mux, err := NewHandler(nil).setupMux(endpoints)
+	assert.Nil(t, mux)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `setupMux: register URL "/users/{name}"`)
+
+	_, err = NewHandler(nil).setupMux(
+		[]types.Endpoint{endpoint.NewEndpoint("/{bad", "GET")},
+	)
+	assert.Error(t, err)
+}
+
+func TestNewHTTPServer_SetupError(t *testing.T) {
+	endpoints := []types.Endpoint{
+		endpoint.NewEndpoint("/users/{id}", "GET"),
+		endpoint.NewEndpoint("/users/{name}", "GET"),
+	}
+	server, err := NewHTTPServer(NewHandler(nil), 0, endpoints)
+	assert.Nil(t, server)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NewHTTPServer: setupMux: register URL")
+
+	assert.Panics(t, func() {
+		DefaultHTTPServer(NewHandler(nil), 0, endpoints)
+	})
+}
+
 func TestStartServer_Normal(t *testing.T) {
 	// Simulate normal shutdown:
 	// Dummy server blocks in ListenAndServe until shutdown is triggered.
@@ -98,6 +160,141 @@ func TestStartServer_Normal(t *testing.T) {
 	assert.True(t, dummyServer.ShutdownCalled)
 }
 
+func TestStartServerTLS(t *testing.T) {
+	dummyServer := NewDummyHTTPServer()
+	stopChan := make(chan os.Signal, 1)
+	handler := NewHandler(nil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- handler.startServerWith(
+			stopChan,
+			dummyServer,
+			func() error {
+				return dummyServer.ListenAndServeTLS("cert.pem", "key.pem")
+			},
+			100*time.Millisecond,
+		)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	stopChan <- os.Interrupt
+
+	assert.NoError(t, <-errCh)
+	assert.True(t, dummyServer.ListenCalled)
+	assert.True(t, dummyServer.ShutdownCalled)
+	assert.Equal(t, "cert.pem", dummyServer.CertFile)
+	assert.Equal(t, "key.pem", dummyServer.KeyFile)
+}
+
+func TestStartServerWithListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	handler := NewHandler(nil)
+	httpServer := &http.Server{
+		Handler: mustSetupMux(t, handler, []types.Endpoint{
+			endpoint.NewEndpoint("/ok", "GET").WithHandler(
+				func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte("ok"))
+				},
+			),
+		}),
+	}
+	stopChan := make(chan os.Signal, 1)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- handler.startServerWith(
+			stopChan,
+			httpServer,
+			func() error { return httpServer.Serve(ln) },
+			time.Second,
+		)
+	}()
+
+	res, err := http.Get("http://" + ln.Addr().String() + "/ok")
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+
+	stopChan <- os.Interrupt
+	assert.NoError(t, <-errCh)
+}
+
+func TestHandler_Shutdown(t *testing.T) {
+	dummyServer := NewDummyHTTPServer()
+	handler := NewHandler(nil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- StartServer(handler, dummyServer, nil)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	handler.Shutdown()
+	// Repeated calls are safe.
+	handler.Shutdown()
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("server did not shut down")
+	}
+	assert.True(t, dummyServer.ShutdownCalled)
+}
+
+func TestHandler_OnShutdown(t *testing.T) {
+	dummyServer := NewDummyHTTPServer()
+	emitterLogger := &recordingEmitterLogger{}
+	stopChan := make(chan os.Signal, 1)
+	var order []string
+	var hookBeforeShutdown bool
+	var startedBeforeHook bool
+	var hookStart time.Time
+	base := NewHandler(emitterLogger).
+		WithPreDrainDelay(30 * time.Millisecond)
+	handler := base.OnShutdown(func(ctx context.Context) {
+		hookStart = time.Now()
+		hookBeforeShutdown = !dummyServer.ShutdownCalled
+		startedBeforeHook = slices.Contains(
+			emitterLogger.eventTypes(), EventShutDownStarted,
+		)
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		order = append(order, "hook1")
+	}).OnShutdown(func(ctx context.Context) {
+		order = append(order, "hook2")
+	})
+	assert.Empty(t, base.shutdownHooks)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- handler.startServer(stopChan, dummyServer, time.Second)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	stopChan <- os.Interrupt
+
+	require.NoError(t, <-errCh)
+	assert.Equal(t, []string{"hook1", "hook2"}, order)
+	assert.True(t, hookBeforeShutdown)
+	assert.True(t, startedBeforeHook)
+	assert.True(t, dummyServer.ShutdownCalled)
+	assert.GreaterOrEqual(t, time.Since(hookStart), 30*time.Millisecond)
+}
+
+func TestHandler_WithSignals(t *testing.T) {
+	handler := NewHandler(nil)
+	assert.Equal(t, DefaultShutdownSignals, handler.signals)
+
+	withHUP := handler.WithSignals(syscall.SIGHUP)
+	assert.Equal(t, []os.Signal{syscall.SIGHUP}, withHUP.signals)
+	assert.Equal(t, DefaultShutdownSignals, handler.signals)
+	assert.Equal(t, DefaultShutdownSignals, withHUP.WithSignals().signals)
+}
+
 func TestStartServer_ListenError(t *testing.T) {
 	// Test the case when ListenAndServe returns an error.
 	expectedErr := errors.New("listen error")
@@ -157,7 +354,7 @@ func TestSetupMuxAndHandlers(t *testing.T) {
 
 	// Use a dummy logger to capture events.
 	handler := NewHandler(nil)
-	mux := handler.setupMux(endpoints)
+	mux := mustSetupMux(t, handler, endpoints)
 	require.NotNil(t, mux)
 
 	// Test /test with allowed GET method.
@@ -186,6 +383,170 @@ func TestSetupMuxAndHandlers(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
 
+func TestMethodNotAllowed_AllowHeader(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	endpoints := []types.Endpoint{
+		endpoint.NewEndpoint("/items", "POST").WithHandler(noop),
+		endpoint.NewEndpoint("/items", "GET").WithHandler(noop),
+		endpoint.NewEndpoint("/items", "DELETE").WithHandler(noop),
+	}
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+
+	req := httptest.NewRequest("PUT", "/items", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.Equal(t, "DELETE, GET, OPTIONS, POST", rr.Header().Get("Allow"))
+}
+
+func TestOptions_Synthesized(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	endpoints := []types.Endpoint{
+		endpoint.NewEndpoint("/items", "GET").WithHandler(noop),
+		endpoint.NewEndpoint("/items", "POST").WithHandler(noop),
+		endpoint.NewEndpoint("/custom", "OPTIONS").WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			},
+		),
+	}
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+
+	req := httptest.NewRequest("OPTIONS", "/items", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "GET, OPTIONS, POST", rr.Header().Get("Allow"))
+
+	// An explicitly registered OPTIONS endpoint is used instead.
+	req = httptest.NewRequest("OPTIONS", "/custom", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusTeapot, rr.Code)
+}
+
+func TestOptions_PreflightUsesEndpointMiddlewares(t *testing.T) {
+	cors := endpoint.NewMiddlewares(middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"POST"},
+	}))
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	endpoints := []types.Endpoint{
+		endpoint.NewEndpoint("/items", "GET").WithHandler(noop),
+		endpoint.NewEndpoint("/items", "POST").
+			WithHandler(noop).
+			WithMiddlewares(cors),
+	}
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+
+	req := httptest.NewRequest("OPTIONS", "/items", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(
+		t,
+		"https://app.example.com",
+		rr.Header().Get("Access-Control-Allow-Origin"),
+	)
+	assert.Equal(t, "POST", rr.Header().Get("Access-Control-Allow-Methods"))
+
+	// A preflight for a method without the middleware gets no CORS headers.
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "GET, OPTIONS, POST", rr.Header().Get("Allow"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestSetupMux_PathParameters(t *testing.T) {
+	endpoints := []types.Endpoint{
+		endpoint.NewEndpoint("/users/{id}", "GET").WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("user " + r.PathValue("id")))
+			},
+		),
+		endpoint.NewEndpoint("/files/{path...}", "GET").WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(r.PathValue("path")))
+			},
+		),
+	}
+	mux := mustSetupMux(t, NewHandler(nil), endpoints)
+
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "user 42", rr.Body.String())
+
+	req = httptest.NewRequest("GET", "/files/a/b.txt", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, "a/b.txt", rr.Body.String())
+
+	// Method not allowed is preserved for patterns.
+	req = httptest.NewRequest("DELETE", "/users/42", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.Equal(t, "GET, OPTIONS", rr.Header().Get("Allow"))
+
+	// Not found is preserved for paths the pattern does not match.
+	req = httptest.NewRequest("GET", "/users/42/posts", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestHandler_WithMiddlewares(t *testing.T) {
+	var events []string
+	global := endpoint.NewMiddlewares(
+		func(next http.Handler) http.Handler {
+			return http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					events = append(events, "global")
+					next.ServeHTTP(w, r)
+				},
+			)
+		},
+	)
+	local := endpoint.NewMiddlewares(
+		func(next http.Handler) http.Handler {
+			return http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					events = append(events, "local")
+					next.ServeHTTP(w, r)
+				},
+			)
+		},
+	)
+	endpoints := []types.Endpoint{
+		endpoint.NewEndpoint("/test", "GET").WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				events = append(events, "handler")
+			},
+		).WithMiddlewares(local),
+	}
+	base := NewHandler(nil)
+	handler := base.WithMiddlewares(global)
+	assert.Nil(t, base.middlewares)
+	mux := mustSetupMux(t, handler, endpoints)
+
+	// Global middleware runs outside the endpoint middleware.
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	assert.Equal(t, []string{"global", "local", "handler"}, events)
+
+	// Global middleware runs for not found routes.
+	events = nil
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, []string{"global"}, events)
+}
+
 func TestMultiplexEndpoints(t *testing.T) {
 	// Create endpoints with different URLs and methods.
 	end1 := endpoint.NewEndpoint("/a", "GET").WithHandler(
@@ -206,7 +567,7 @@ func TestMultiplexEndpoints(t *testing.T) {
 	endpoints := []types.Endpoint{end1, end2, end3}
 
 	handler := NewHandler(nil)
-	muxed := handler.multiplexEndpoints(endpoints)
+	muxed, _ := handler.multiplexEndpoints(endpoints)
 
 	// Check that the keys "/a" and "/b" exist.
 	assert.Contains(t, muxed, "/a")
@@ -222,6 +583,40 @@ func TestMultiplexEndpoints(t *testing.T) {
 	assert.Contains(t, methodsB, "GET")
 }
 
+// TestMultiplexEndpoints_Duplicate verifies that registering the same URL
+// and method twice emits a warning and the later endpoint wins.
+func TestMultiplexEndpoints_Duplicate(t *testing.T) {
+	first := endpoint.NewEndpoint("/users", "POST").WithHandler(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("first"))
+		},
+	)
+	second := endpoint.NewEndpoint("/users", "POST").WithHandler(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("second"))
+		},
+	)
+	emitterLogger := &recordingEmitterLogger{}
+	mux := mustSetupMux(
+		t, NewHandler(emitterLogger), []types.Endpoint{first, second},
+	)
+
+	require.Contains(t, emitterLogger.eventTypes(), EventDuplicateRoute)
+	for _, event := range emitterLogger.events {
+		if event.Type == EventDuplicateRoute {
+			assert.Equal(
+				t,
+				map[string]any{"path": "/users", "method": "POST"},
+				event.Data,
+			)
+			assert.Contains(t, event.Message, "POST /users")
+		}
+	}
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("POST", "/users", nil))
+	assert.Equal(t, "second", rr.Body.String())
+}
+
 func TestServerPanicHandler(t *testing.T) {
 	// Create a handler that panics.
 	panicHandler := http.HandlerFunc(
@@ -239,3 +634,130 @@ func TestServerPanicHandler(t *testing.T) {
 	// The panic should be recovered and return an internal server error.
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
+
+// recordingEmitterLogger records the events passed to it.
+type recordingEmitterLogger struct {
+	mu     sync.Mutex
+	events []*utiltypes.Event
+	params [][]any
+}
+
+func (l *recordingEmitterLogger) record(event *utiltypes.Event, p ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	l.params = append(l.params, p)
+}
+
+// eventTypes returns the types of the recorded events.
+func (l *recordingEmitterLogger) eventTypes() []utiltypes.EventType {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var result []utiltypes.EventType
+	for _, event := range l.events {
+		result = append(result, event.Type)
+	}
+	return result
+}
+
+func (l *recordingEmitterLogger) Debug(e *utiltypes.Event, p ...any) {
+	l.record(e, p...)
+}
+func (l *recordingEmitterLogger) Info(e *utiltypes.Event, p ...any) {
+	l.record(e, p...)
+}
+func (l *recordingEmitterLogger) Warn(e *utiltypes.Event, p ...any) {
+	l.record(e, p...)
+}
+func (l *recordingEmitterLogger) Error(e *utiltypes.Event, p ...any) {
+	l.record(e, p...)
+}
+func (l *recordingEmitterLogger) Fatal(e *utiltypes.Event, p ...any) {
+	l.record(e, p...)
+}
+func (l *recordingEmitterLogger) Trace(e *utiltypes.Event, p ...any) {
+	l.record(e, p...)
+}
+
+func TestServerPanicHandler_RequestID(t *testing.T) {
+	panicHandler := http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			panic("test panic")
+		},
+	)
+	emitterLogger := &recordingEmitterLogger{}
+	handler := NewHandler(emitterLogger)
+	wrapped := handler.serverPanicHandler(
+		middleware.RequestID(middleware.RequestIDOptions{})(panicHandler),
+	)
+
+	req := httptest.NewRequest("GET", "/panic", nil)
+	req.Header.Set(middleware.DefaultRequestIDHeader, "req-1")
+	rr := httptest.NewRecorder()
+	wrapped.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	require.Len(t, emitterLogger.events, 1)
+	assert.Equal(t, EventPanic, emitterLogger.events[0].Type)
+	data := emitterLogger.events[0].Data.(map[string]any)
+	assert.Equal(t, "req-1", data["request_id"])
+}
+
+func TestServerPanicHandler_Event(t *testing.T) {
+	panicHandler := http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			panic("test panic")
+		},
+	)
+	emitterLogger := &recordingEmitterLogger{}
+	handler := NewHandler(emitterLogger)
+	wrapped := handler.serverPanicHandler(panicHandler)
+
+	req := httptest.NewRequest(
+		"POST", "/panic?q=1", strings.NewReader("body"),
+	)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Large", strings.Repeat("a", 3*maxPanicRequestDump))
+	rr := httptest.NewRecorder()
+	wrapped.ServeHTTP(rr, req)
+
+	require.Len(t, emitterLogger.events, 1)
+	event := emitterLogger.events[0]
+	assert.Equal(t, EventPanic, event.Type)
+	assert.Contains(t, event.Message, "test panic")
+	data := event.Data.(map[string]any)
+	stack := data["stack"].([]string)
+	assert.NotEmpty(t, stack)
+	assert.Contains(t, strings.Join(stack, "\n"), "serverPanicHandler")
+
+	dump := data["request"].(string)
+	assert.True(t, strings.HasPrefix(dump, "POST /panic?q=1 HTTP/1.1"))
+	assert.Contains(t, dump, "Authorization: [REDACTED]")
+	assert.NotContains(t, dump, "secret")
+	assert.NotContains(t, dump, "body")
+	assert.True(t, strings.HasSuffix(dump, "...(truncated)"))
+	assert.Len(t, dump, maxPanicRequestDump+len("...(truncated)"))
+}
+
+func TestServerPanicHandler_LogFields(t *testing.T) {
+	panicHandler := http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			panic("test panic")
+		},
+	)
+	emitterLogger := &recordingEmitterLogger{}
+	handler := NewHandler(emitterLogger)
+	wrapped := handler.serverPanicHandler(panicHandler)
+
+	req := httptest.NewRequest("GET", "/panic", nil)
+	req = req.WithContext(
+		util.WithLogFields(req.Context(), "tenant", "t1"),
+	)
+	rr := httptest.NewRecorder()
+	wrapped.ServeHTTP(rr, req)
+
+	require.Len(t, emitterLogger.events, 1)
+	data := emitterLogger.events[0].Data.(map[string]any)
+	assert.Equal(t, []any{"tenant", "t1"}, data["fields"])
+	assert.Equal(t, []any{"tenant", "t1"}, emitterLogger.params[0])
+}
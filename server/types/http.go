@@ -1,9 +1,14 @@
 package types
 
-import "context"
+import (
+	"context"
+	"net"
+)
 
 // HTTPServer represents an HTTP server.
 type HTTPServer interface {
-	ListenAndServe() error              // Start the server.
-	Shutdown(ctx context.Context) error // Shut down the server.
+	ListenAndServe() error                            // Start the server.
+	ListenAndServeTLS(certFile, keyFile string) error // Start with TLS.
+	Serve(ln net.Listener) error                      // Serve on a listener.
+	Shutdown(ctx context.Context) error               // Shut down the server.
 }
@@ -3,6 +3,7 @@ package util
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/pureapi/pureapi-core/util/types"
 )
@@ -13,11 +14,14 @@ type DefaultAPIError struct {
 	ErrData    any    `json:"data,omitempty"`
 	ErrMessage string `json:"message,omitempty"`
 	ErrOrigin  string `json:"origin,omitempty"`
+	ErrStatus  int    `json:"status,omitempty"`
+	Err        error  `json:"-"`
 }
 
 var _ types.APIError = (*DefaultAPIError)(nil)
 
-// NewAPIError returns a new error with the given ID.
+// NewAPIError returns a new error with the given ID. Register the error with
+// RegisterAPIError to list it in Catalog.
 //
 // Parameters:
 //   - id: The ID of the error.
@@ -30,10 +34,13 @@ func NewAPIError(id string) *DefaultAPIError {
 		ErrData:    nil,
 		ErrMessage: "",
 		ErrOrigin:  "",
+		ErrStatus:  0,
+		Err:        nil,
 	}
 }
 
-// APIErrorFrom converts an APIError to a DefaultAPIError.
+// APIErrorFrom converts an APIError to a DefaultAPIError. The status and the
+// wrapped error are kept if err is a DefaultAPIError.
 //
 // Parameters:
 //   - err: The APIError to convert.
@@ -41,28 +48,53 @@ func NewAPIError(id string) *DefaultAPIError {
 // Returns:
 //   - *defaultAPIError: A new defaultAPIError instance.
 func APIErrorFrom(err types.APIError) *DefaultAPIError {
+	status := 0
+	var wrapped error
+	if defaultErr, ok := err.(*DefaultAPIError); ok {
+		status = defaultErr.ErrStatus
+		wrapped = defaultErr.Err
+	}
 	return &DefaultAPIError{
 		ErrID:      err.ID(),
 		ErrData:    err.Data(),
 		ErrMessage: err.Message(),
 		ErrOrigin:  err.Origin(),
+		ErrStatus:  status,
+		Err:        wrapped,
 	}
 }
 
-// Alias type to avoid infinite recursion in JSON marshaling.
-type defaultAPIErrorAlias DefaultAPIError
+// apiErrorJSON is the stable JSON form of an APIError. The ID is always
+// present. The message, data, origin and status are omitted when they are
+// empty, nil, empty and zero respectively.
+type apiErrorJSON struct {
+	ID      string `json:"id"`
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data,omitempty"`
+	Origin  string `json:"origin,omitempty"`
+	Status  int    `json:"status,omitempty"`
+}
 
-// MarshalJSON implements custom JSON marshaling.
+// MarshalJSON implements custom JSON marshaling. It produces the shape
+// {"id":..., "message":..., "data":..., "origin":..., "status":...} with
+// the omitempty rules of apiErrorJSON. Data is marshaled as is. The wrapped
+// error is never marshaled.
 //
 // Returns:
 //   - []byte: The JSON representation of the error.
 //   - error: An error if the marshaling fails.
 func (e *DefaultAPIError) MarshalJSON() ([]byte, error) {
-	alias := defaultAPIErrorAlias(*e)
-	return json.Marshal(alias)
+	return json.Marshal(apiErrorJSON{
+		ID:      e.ErrID,
+		Message: e.ErrMessage,
+		Data:    e.ErrData,
+		Origin:  e.ErrOrigin,
+		Status:  e.ErrStatus,
+	})
 }
 
-// UnmarshalJSON implements custom JSON unmarshaling.
+// UnmarshalJSON implements custom JSON unmarshaling. It accepts the shape
+// produced by MarshalJSON. Data is decoded into generic JSON values.
 //
 // Parameters:
 //   - data: The JSON data to unmarshal.
@@ -70,11 +102,18 @@ func (e *DefaultAPIError) MarshalJSON() ([]byte, error) {
 // Returns:
 //   - error: An error if the unmarshaling fails.
 func (e *DefaultAPIError) UnmarshalJSON(data []byte) error {
-	var alias defaultAPIErrorAlias
-	if err := json.Unmarshal(data, &alias); err != nil {
+	var wire apiErrorJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
 		return err
 	}
-	*e = DefaultAPIError(alias)
+	*e = DefaultAPIError{
+		ErrID:      wire.ID,
+		ErrData:    wire.Data,
+		ErrMessage: wire.Message,
+		ErrOrigin:  wire.Origin,
+		ErrStatus:  wire.Status,
+		Err:        nil,
+	}
 	return nil
 }
 
@@ -130,6 +169,33 @@ func (e *DefaultAPIError) WithOrigin(origin string) *DefaultAPIError {
 	return &new
 }
 
+// WithStatus returns a new error with the given HTTP status code.
+//
+// Parameters:
+//   - code: The HTTP status code of the error.
+//
+// Returns:
+//   - *defaultAPIError: A new defaultAPIError.
+func (e *DefaultAPIError) WithStatus(code int) *DefaultAPIError {
+	new := *e
+	new.ErrStatus = code
+	return &new
+}
+
+// WithError returns a new error wrapping the given error. The wrapped error
+// is not marshaled to JSON but can be reached with errors.Is and errors.As.
+//
+// Parameters:
+//   - err: The error to wrap.
+//
+// Returns:
+//   - *defaultAPIError: A new defaultAPIError.
+func (e *DefaultAPIError) WithError(err error) *DefaultAPIError {
+	new := *e
+	new.Err = err
+	return &new
+}
+
 // Error returns the full error message as a string. If the error has a message,
 // it returns the ID followed by the message. Otherwise, it returns just the ID.
 //
@@ -142,6 +208,26 @@ func (e *DefaultAPIError) Error() string {
 	return e.ErrID
 }
 
+// LocalizedMessage returns the message of the error resolved for the locale.
+// It falls back to the raw message if the resolver is nil or has no message.
+//
+// Parameters:
+//   - resolver: The message resolver.
+//   - locale: The locale to resolve the message for.
+//
+// Returns:
+//   - string: The localized message.
+func (e *DefaultAPIError) LocalizedMessage(
+	resolver types.MessageResolver, locale string,
+) string {
+	if resolver != nil {
+		if message, ok := resolver.Resolve(e.ErrID, locale, e.ErrData); ok {
+			return message
+		}
+	}
+	return e.ErrMessage
+}
+
 // ID returns the ID of the error.
 //
 // Returns:
@@ -173,3 +259,35 @@ func (e *DefaultAPIError) Message() string {
 func (e *DefaultAPIError) Origin() string {
 	return e.ErrOrigin
 }
+
+// HTTPStatus returns the HTTP status code of the error. It defaults to 500
+// when no status is set.
+//
+// Returns:
+//   - int: The HTTP status code of the error.
+func (e *DefaultAPIError) HTTPStatus() int {
+	if e.ErrStatus == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.ErrStatus
+}
+
+// Unwrap returns the wrapped error.
+//
+// Returns:
+//   - error: The wrapped error, or nil if none is set.
+func (e *DefaultAPIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is an APIError with the same ID.
+//
+// Parameters:
+//   - target: The target error.
+//
+// Returns:
+//   - bool: True if the target is an APIError with the same ID.
+func (e *DefaultAPIError) Is(target error) bool {
+	apiErr, ok := target.(types.APIError)
+	return ok && apiErr.ID() == e.ErrID
+}
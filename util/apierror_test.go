@@ -1,6 +1,12 @@
 package util
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -116,3 +122,122 @@ func (s *APIErrorTestSuite) Test_Error() {
 	errWithMsg := base.WithMessage(msg)
 	s.Equal("E004: "+msg, errWithMsg.Error())
 }
+
+// Test_WithStatus verifies that WithStatus returns a new APIError with the
+// status set and that HTTPStatus defaults to 500.
+func (s *APIErrorTestSuite) Test_WithStatus() {
+	base := NewAPIError("E005")
+	s.Equal(http.StatusInternalServerError, base.HTTPStatus())
+
+	newErr := base.WithStatus(http.StatusNotFound)
+	s.NotSame(base, newErr, "WithStatus should return a new instance")
+	s.Equal(base.ErrID, newErr.ErrID)
+	s.Equal(http.StatusNotFound, newErr.HTTPStatus())
+	s.Zero(base.ErrStatus)
+
+	s.Equal(http.StatusNotFound, APIErrorFrom(newErr).HTTPStatus())
+}
+
+// Test_MarshalJSON_Status verifies that a zero status is omitted from JSON.
+func (s *APIErrorTestSuite) Test_MarshalJSON_Status() {
+	data, err := json.Marshal(NewAPIError("E006"))
+	s.Require().NoError(err)
+	s.JSONEq(`{"id":"E006"}`, string(data))
+
+	data, err = json.Marshal(NewAPIError("E006").WithStatus(400))
+	s.Require().NoError(err)
+	s.JSONEq(`{"id":"E006","status":400}`, string(data))
+}
+
+// Test_WithError verifies that wrapped errors can be reached through a
+// translation into an APIError.
+func (s *APIErrorTestSuite) Test_WithError() {
+	errDriver := errors.New("driver error")
+	errInvalidPredicate := NewAPIError("INVALID_PREDICATE")
+
+	wrapped := fmt.Errorf("query: %w", errDriver)
+	translated := fmt.Errorf(
+		"translate: %w", errInvalidPredicate.WithError(wrapped),
+	)
+
+	s.ErrorIs(translated, errDriver)
+	s.ErrorIs(translated, errInvalidPredicate)
+	s.NotErrorIs(translated, NewAPIError("OTHER"))
+
+	var apiErr *DefaultAPIError
+	s.Require().ErrorAs(translated, &apiErr)
+	s.Equal("INVALID_PREDICATE", apiErr.ID())
+	s.Equal(wrapped, apiErr.Unwrap())
+	s.Nil(errInvalidPredicate.Unwrap())
+}
+
+// Test_MarshalJSON_Err verifies that the wrapped error is not marshaled.
+func (s *APIErrorTestSuite) Test_MarshalJSON_Err() {
+	apiErr := NewAPIError("E007").WithError(errors.New("secret"))
+	data, err := json.Marshal(apiErr)
+	s.Require().NoError(err)
+	s.JSONEq(`{"id":"E007"}`, string(data))
+}
+
+// Test_MarshalJSON_Golden verifies the serialized form of API errors against
+// the golden files in testdata.
+func (s *APIErrorTestSuite) Test_MarshalJSON_Golden() {
+	testCases := []struct {
+		name   string
+		apiErr *DefaultAPIError
+	}{
+		{"apierror_minimal", NewAPIError("E100")},
+		{
+			"apierror_full",
+			NewAPIError("E101").
+				WithMessage("invalid input").
+				WithData(map[string]any{
+					"fields": []map[string]any{
+						{"field": "name", "code": "required"},
+					},
+					"count": 1,
+				}).
+				WithOrigin("api").
+				WithStatus(http.StatusBadRequest).
+				WithError(errors.New("not marshaled")),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			golden, err := os.ReadFile(
+				filepath.Join("testdata", tc.name+".golden"),
+			)
+			s.Require().NoError(err)
+			data, err := json.Marshal(tc.apiErr)
+			s.Require().NoError(err)
+			s.Equal(string(golden), string(data)+"\n")
+		})
+	}
+}
+
+// Test_UnmarshalJSON_RoundTrip verifies that marshaled errors unmarshal to
+// an equal error with nested data preserved.
+func (s *APIErrorTestSuite) Test_UnmarshalJSON_RoundTrip() {
+	golden, err := os.ReadFile(
+		filepath.Join("testdata", "apierror_full.golden"),
+	)
+	s.Require().NoError(err)
+
+	var apiErr DefaultAPIError
+	s.Require().NoError(json.Unmarshal(golden, &apiErr))
+	s.Equal("E101", apiErr.ID())
+	s.Equal("invalid input", apiErr.Message())
+	s.Equal("api", apiErr.Origin())
+	s.Equal(http.StatusBadRequest, apiErr.HTTPStatus())
+	s.Equal(map[string]any{
+		"fields": []any{
+			map[string]any{"field": "name", "code": "required"},
+		},
+		"count": float64(1),
+	}, apiErr.Data())
+
+	data, err := json.Marshal(&apiErr)
+	s.Require().NoError(err)
+	s.Equal(string(golden), string(data)+"\n")
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pureapi/pureapi-core/util/types"
+)
+
+// ErrDuplicateAPIError is returned when an API error ID is registered twice.
+var ErrDuplicateAPIError = errors.New("duplicate API error ID")
+
+// apiErrorCatalog holds the known API errors.
+type apiErrorCatalog struct {
+	mu         sync.RWMutex
+	registered map[string]types.APIError
+}
+
+// catalog is the package-level API error catalog.
+var catalog = newAPIErrorCatalog()
+
+// newAPIErrorCatalog returns a new empty API error catalog.
+func newAPIErrorCatalog() *apiErrorCatalog {
+	return &apiErrorCatalog{
+		registered: map[string]types.APIError{},
+	}
+}
+
+// RegisterAPIError registers an API error in the catalog. Registering the
+// same ID twice returns an error wrapping ErrDuplicateAPIError.
+//
+// Parameters:
+//   - apiErr: The API error to register.
+//
+// Returns:
+//   - error: An error if the ID is already registered.
+func RegisterAPIError(apiErr types.APIError) error {
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+	if _, ok := catalog.registered[apiErr.ID()]; ok {
+		return fmt.Errorf(
+			"RegisterAPIError: %w: %s", ErrDuplicateAPIError, apiErr.ID(),
+		)
+	}
+	catalog.registered[apiErr.ID()] = apiErr
+	return nil
+}
+
+// MustRegister registers the API errors in the catalog and panics on a
+// duplicate ID. It is meant for validating error definitions at init time.
+//
+// Parameters:
+//   - apiErrs: The API errors to register.
+func MustRegister(apiErrs ...types.APIError) {
+	for _, apiErr := range apiErrs {
+		if err := RegisterAPIError(apiErr); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Catalog returns the registered API errors sorted by ID. Errors created
+// with NewAPIError are not listed unless they are registered with
+// RegisterAPIError or MustRegister.
+//
+// Returns:
+//   - []types.APIError: The known API errors.
+func Catalog() []types.APIError {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+	errs := make([]types.APIError, 0, len(catalog.registered))
+	for _, apiErr := range catalog.registered {
+		errs = append(errs, apiErr)
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].ID() < errs[j].ID()
+	})
+	return errs
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// APIErrorCatalogTestSuite defines a test suite for the API error catalog.
+type APIErrorCatalogTestSuite struct {
+	suite.Suite
+	original *apiErrorCatalog
+}
+
+// TestAPIErrorCatalogTestSuite runs the test suite.
+func TestAPIErrorCatalogTestSuite(t *testing.T) {
+	suite.Run(t, new(APIErrorCatalogTestSuite))
+}
+
+// SetupTest replaces the package-level catalog with an empty one.
+func (s *APIErrorCatalogTestSuite) SetupTest() {
+	s.original = catalog
+	catalog = newAPIErrorCatalog()
+}
+
+// TearDownTest restores the package-level catalog.
+func (s *APIErrorCatalogTestSuite) TearDownTest() {
+	catalog = s.original
+}
+
+// Test_RegisterAPIError_Duplicate verifies that duplicate IDs are detected.
+func (s *APIErrorCatalogTestSuite) Test_RegisterAPIError_Duplicate() {
+	s.Require().NoError(RegisterAPIError(&DefaultAPIError{ErrID: "E1"}))
+	err := RegisterAPIError(&DefaultAPIError{ErrID: "E1"})
+	s.ErrorIs(err, ErrDuplicateAPIError)
+	s.Contains(err.Error(), "E1")
+}
+
+// Test_MustRegister verifies that MustRegister panics on duplicate IDs.
+func (s *APIErrorCatalogTestSuite) Test_MustRegister() {
+	s.NotPanics(func() {
+		MustRegister(
+			&DefaultAPIError{ErrID: "E1"}, &DefaultAPIError{ErrID: "E2"},
+		)
+	})
+	s.Panics(func() {
+		MustRegister(&DefaultAPIError{ErrID: "E2"})
+	})
+}
+
+// Test_Catalog verifies that the catalog contains only the registered
+// errors, sorted by ID.
+func (s *APIErrorCatalogTestSuite) Test_Catalog() {
+	registered := (&DefaultAPIError{ErrID: "B"}).WithMessage("registered")
+	MustRegister(NewAPIError("C"), registered, NewAPIError("A"))
+	NewAPIError("D")
+
+	errs := Catalog()
+	s.Require().Len(errs, 3)
+	s.Equal("A", errs[0].ID())
+	s.Equal(registered, errs[1])
+	s.Equal("C", errs[2].ID())
+}
@@ -6,9 +6,12 @@ import "github.com/pureapi/pureapi-core/util/types"
 type emitterLogger struct {
 	eventEmitter    types.EventEmitter
 	loggerFactoryFn types.LoggerFactoryFn
+	minLogLevel     types.LogLevel
+	minEmitLevel    types.LogLevel
 }
 
-// NewEmitterLogger creates a new EmitterLogger.
+// NewEmitterLogger creates a new EmitterLogger. It logs and emits at all
+// levels by default.
 //
 // Parameters:
 //   - eventEmitter: An EventEmitter.
@@ -22,9 +25,54 @@ func NewEmitterLogger(
 	return &emitterLogger{
 		eventEmitter:    eventEmitter,
 		loggerFactoryFn: loggerFactoryFn,
+		minLogLevel:     types.LevelTrace,
+		minEmitLevel:    types.LevelTrace,
 	}
 }
 
+// WithMinLevel returns a new EmitterLogger that only logs at or above the
+// given level. Events are still emitted at all levels unless
+// WithMinEmitLevel is also set.
+//
+// Parameters:
+//   - level: The minimum level to log.
+//
+// Returns:
+//   - *emitterLogger: A new emitterLogger instance.
+func (e *emitterLogger) WithMinLevel(level types.LogLevel) *emitterLogger {
+	new := *e
+	new.minLogLevel = level
+	return &new
+}
+
+// WithMinEmitLevel returns a new EmitterLogger that only emits events at or
+// above the given level.
+//
+// Parameters:
+//   - level: The minimum level to emit events at.
+//
+// Returns:
+//   - *emitterLogger: A new emitterLogger instance.
+func (e *emitterLogger) WithMinEmitLevel(
+	level types.LogLevel,
+) *emitterLogger {
+	new := *e
+	new.minEmitLevel = level
+	return &new
+}
+
+// Enabled reports whether a call at the level would log or emit. Callers can
+// use it to skip building expensive events.
+//
+// Parameters:
+//   - level: The level to check.
+//
+// Returns:
+//   - bool: True if the call would log or emit.
+func (e *emitterLogger) Enabled(level types.LogLevel) bool {
+	return e.canLog(level) || e.canEmit(level)
+}
+
 // NewNoopEmitterLogger creates a new EmitterLogger that does nothing.
 //
 // Returns:
@@ -39,8 +87,8 @@ func NewNoopEmitterLogger() *emitterLogger {
 //   - event The event to emit and log.
 //   - factoryParams: The parameters to pass to the logger factory function.
 func (e *emitterLogger) Debug(event *types.Event, factoryParams ...any) {
-	e.emitIfCan(event)
-	if e.loggerFactoryFn != nil {
+	e.emitIfCan(types.LevelDebug, event)
+	if e.canLog(types.LevelDebug) {
 		e.loggerFactoryFn(factoryParams...).Debug(event.Message)
 	}
 }
@@ -51,8 +99,8 @@ func (e *emitterLogger) Debug(event *types.Event, factoryParams ...any) {
 //   - event The event to emit and log.
 //   - factoryParams: The parameters to pass to the logger factory function.
 func (e *emitterLogger) Trace(event *types.Event, factoryParams ...any) {
-	e.emitIfCan(event)
-	if e.loggerFactoryFn != nil {
+	e.emitIfCan(types.LevelTrace, event)
+	if e.canLog(types.LevelTrace) {
 		e.loggerFactoryFn(factoryParams...).Trace(event.Message)
 	}
 }
@@ -63,8 +111,8 @@ func (e *emitterLogger) Trace(event *types.Event, factoryParams ...any) {
 //   - event The event to emit and log.
 //   - factoryParams: The parameters to pass to the logger factory function.
 func (e *emitterLogger) Info(event *types.Event, factoryParams ...any) {
-	e.emitIfCan(event)
-	if e.loggerFactoryFn != nil {
+	e.emitIfCan(types.LevelInfo, event)
+	if e.canLog(types.LevelInfo) {
 		e.loggerFactoryFn(factoryParams...).Info(event.Message)
 	}
 }
@@ -75,8 +123,8 @@ func (e *emitterLogger) Info(event *types.Event, factoryParams ...any) {
 //   - event The event to emit and log.
 //   - factoryParams: The parameters to pass to the logger factory function.
 func (e *emitterLogger) Warn(event *types.Event, factoryParams ...any) {
-	e.emitIfCan(event)
-	if e.loggerFactoryFn != nil {
+	e.emitIfCan(types.LevelWarn, event)
+	if e.canLog(types.LevelWarn) {
 		e.loggerFactoryFn(factoryParams...).Warn(event.Message)
 	}
 }
@@ -87,8 +135,8 @@ func (e *emitterLogger) Warn(event *types.Event, factoryParams ...any) {
 //   - event The event to emit and log.
 //   - factoryParams: The parameters to pass to the logger factory function.
 func (e *emitterLogger) Error(event *types.Event, factoryParams ...any) {
-	e.emitIfCan(event)
-	if e.loggerFactoryFn != nil {
+	e.emitIfCan(types.LevelError, event)
+	if e.canLog(types.LevelError) {
 		e.loggerFactoryFn(factoryParams...).Error(event.Message)
 	}
 }
@@ -99,15 +147,26 @@ func (e *emitterLogger) Error(event *types.Event, factoryParams ...any) {
 //   - event The event to emit and log.
 //   - factoryParams: The parameters to pass to the logger factory function.
 func (e *emitterLogger) Fatal(event *types.Event, factoryParams ...any) {
-	e.emitIfCan(event)
-	if e.loggerFactoryFn != nil {
+	e.emitIfCan(types.LevelFatal, event)
+	if e.canLog(types.LevelFatal) {
 		e.loggerFactoryFn(factoryParams...).Fatal(event.Message)
 	}
 }
 
-// emitIfCan emits the event if the event emitter is not nil.
-func (e *emitterLogger) emitIfCan(event *types.Event) {
-	if e.eventEmitter != nil {
+// emitIfCan emits the event if the event emitter is not nil and the level is
+// not below the minimum emit level.
+func (e *emitterLogger) emitIfCan(level types.LogLevel, event *types.Event) {
+	if e.canEmit(level) {
 		e.eventEmitter.Emit(event)
 	}
 }
+
+// canEmit reports whether an event at the level would be emitted.
+func (e *emitterLogger) canEmit(level types.LogLevel) bool {
+	return e.eventEmitter != nil && level >= e.minEmitLevel
+}
+
+// canLog reports whether a message at the level would be logged.
+func (e *emitterLogger) canLog(level types.LogLevel) bool {
+	return e.loggerFactoryFn != nil && level >= e.minLogLevel
+}
@@ -20,11 +20,27 @@ func (f *FakeEventEmitter) RegisterListener(
 	return f
 }
 
+func (f *FakeEventEmitter) RegisterOnce(
+	eventType types.EventType, callback types.EventCallback,
+) string {
+	return ""
+}
+
 func (f *FakeEventEmitter) RemoveListener(
 	eventType types.EventType, id string,
 ) {
 }
 
+func (f *FakeEventEmitter) RemoveAllListeners(eventType types.EventType) {
+}
+
+func (f *FakeEventEmitter) RemoveAllListenersForAllTypes() {
+}
+
+func (f *FakeEventEmitter) ListenerCount(eventType types.EventType) int {
+	return 0
+}
+
 func (f *FakeEventEmitter) Emit(event *types.Event) {
 	f.EmittedEvents = append(f.EmittedEvents, event)
 }
@@ -252,3 +268,40 @@ func (suite *EmitterLoggerTestSuite) TestNoopEmitterLogger() {
 	el.Fatal(event)
 	// Nothing to assert since it's a no-op.
 }
+
+// TestWithMinLevel verifies that calls below the log threshold do not log
+// but still emit.
+func (suite *EmitterLoggerTestSuite) TestWithMinLevel() {
+	el := NewEmitterLogger(suite.fakeEmitter, suite.fakeLoggerFactory).
+		WithMinLevel(types.LevelInfo)
+	el.Debug(types.NewEvent("Debug", "debug message"))
+	el.Trace(types.NewEvent("Trace", "trace message"))
+	assert.Equal(
+		suite.T(), "", suite.fakeLogger.LastCalledMethod,
+		"Logger should not be called below the threshold",
+	)
+	assert.Len(suite.T(), suite.fakeEmitter.EmittedEvents, 2)
+
+	el.Info(types.NewEvent("Info", "info message"))
+	assert.Equal(suite.T(), "Info", suite.fakeLogger.LastCalledMethod)
+}
+
+// TestWithMinEmitLevel verifies that calls below both thresholds neither log
+// nor emit.
+func (suite *EmitterLoggerTestSuite) TestWithMinEmitLevel() {
+	el := NewEmitterLogger(suite.fakeEmitter, suite.fakeLoggerFactory).
+		WithMinLevel(types.LevelWarn).
+		WithMinEmitLevel(types.LevelWarn)
+	suite.False(el.Enabled(types.LevelInfo))
+	suite.True(el.Enabled(types.LevelWarn))
+
+	el.Debug(types.NewEvent("Debug", "debug message"))
+	el.Trace(types.NewEvent("Trace", "trace message"))
+	el.Info(types.NewEvent("Info", "info message"))
+	assert.Len(suite.T(), suite.fakeEmitter.EmittedEvents, 0)
+	assert.Equal(suite.T(), "", suite.fakeLogger.LastCalledMethod)
+
+	el.Error(types.NewEvent("Error", "error message"))
+	assert.Len(suite.T(), suite.fakeEmitter.EmittedEvents, 1)
+	assert.Equal(suite.T(), "Error", suite.fakeLogger.LastCalledMethod)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pureapi/pureapi-core/util/types"
@@ -21,6 +22,7 @@ type defaultEventEmitter struct {
 	mu        sync.RWMutex   // Mutex for thread safety when emitting events.
 	counter   int            // Used to generate unique IDs for listeners.
 	timeout   *time.Duration // Optional timeout for each callback.
+	sync      bool           // Run callbacks on the caller's goroutine.
 }
 
 // defaultEventEmitter implements the EventEmitter interface.
@@ -39,6 +41,7 @@ func NewEventEmitter() *defaultEventEmitter {
 		mu:        sync.RWMutex{},
 		counter:   0,
 		timeout:   nil,
+		sync:      false,
 	}
 	return eventEmitter
 }
@@ -57,6 +60,20 @@ func (e *defaultEventEmitter) WithTimeout(
 ) *defaultEventEmitter {
 	new := NewEventEmitter()
 	new.timeout = timeout
+	new.sync = e.sync
+	return new
+}
+
+// WithSync makes the emitter run the callbacks synchronously on the goroutine
+// calling Emit, in registration order. The timeout is ignored for
+// synchronous callbacks. It will return a new eventEmitter.
+//
+// Returns:
+//   - *defaultEventEmitter: A new defaultEventEmitter.
+func (e *defaultEventEmitter) WithSync() *defaultEventEmitter {
+	new := NewEventEmitter()
+	new.timeout = e.timeout
+	new.sync = true
 	return new
 }
 
@@ -71,6 +88,42 @@ func (e *defaultEventEmitter) WithTimeout(
 func (e *defaultEventEmitter) RegisterListener(
 	eventType types.EventType, callback types.EventCallback,
 ) types.EventEmitter {
+	e.addListener(eventType, func(string) types.EventCallback {
+		return callback
+	})
+	return e
+}
+
+// RegisterOnce registers a listener that is removed after its first
+// invocation. The callback is invoked exactly once even if the event is
+// emitted concurrently.
+//
+// Parameters:
+//   - eventType: The type of the event.
+//   - callback: The function to call when the event is emitted.
+//
+// Returns:
+//   - string: The ID of the listener, usable with RemoveListener.
+func (e *defaultEventEmitter) RegisterOnce(
+	eventType types.EventType, callback types.EventCallback,
+) string {
+	return e.addListener(eventType, func(id string) types.EventCallback {
+		var fired atomic.Bool
+		return func(event *types.Event) {
+			if !fired.CompareAndSwap(false, true) {
+				return
+			}
+			e.RemoveListener(eventType, id)
+			callback(event)
+		}
+	})
+}
+
+// addListener adds a listener with a generated ID. The callback is built
+// with the ID so that it can refer to its own listener.
+func (e *defaultEventEmitter) addListener(
+	eventType types.EventType, callbackFn func(id string) types.EventCallback,
+) string {
 	// Generate a unique ID for the listener.
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -80,9 +133,9 @@ func (e *defaultEventEmitter) RegisterListener(
 	// Add the listener to the list.
 	e.listeners[eventType] = append(e.listeners[eventType], eventListener{
 		id:       id,
-		callback: callback,
+		callback: callbackFn(id),
 	})
-	return e
+	return id
 }
 
 // RemoveListener removes a listener for a specific event type.
@@ -101,18 +154,52 @@ func (e *defaultEventEmitter) RemoveListener(
 	if list, found := e.listeners[eventType]; found {
 		for i, l := range list {
 			if l.id == id {
-				// Remove the listener with the matching ID.
-				e.listeners[eventType] = append(list[:i], list[i+1:]...)
+				// Remove the listener with the matching ID. A new slice is
+				// built so that emits iterating the old one are unaffected.
+				remaining := make([]eventListener, 0, len(list)-1)
+				remaining = append(remaining, list[:i]...)
+				e.listeners[eventType] = append(remaining, list[i+1:]...)
 				break
 			}
 		}
 	}
 }
 
+// RemoveAllListeners removes all listeners for a specific event type.
+//
+// Parameters:
+//   - eventType: The type of the event.
+func (e *defaultEventEmitter) RemoveAllListeners(eventType types.EventType) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.listeners, eventType)
+}
+
+// RemoveAllListenersForAllTypes removes all listeners for all event types.
+func (e *defaultEventEmitter) RemoveAllListenersForAllTypes() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.listeners = make(map[types.EventType][]eventListener)
+}
+
+// ListenerCount returns the number of listeners for a specific event type.
+//
+// Parameters:
+//   - eventType: The type of the event.
+//
+// Returns:
+//   - int: The number of listeners.
+func (e *defaultEventEmitter) ListenerCount(eventType types.EventType) int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.listeners[eventType])
+}
+
 // Emit emits an event to all registered listeners. It runs each callback in a
-// separate goroutine. If timeout is set for the eventEmitter, the callbacks
-// will be run with the specified timeout. If the timeout is exceeded, an error
-// message will be printed to stderr.
+// separate goroutine, or in registration order on the calling goroutine if
+// the emitter is synchronous. If timeout is set for the eventEmitter, the
+// asynchronous callbacks will be run with the specified timeout. If the
+// timeout is exceeded, an error message will be printed to stderr.
 //
 // Parameters:
 //   - event: The event to emit.
@@ -123,6 +210,13 @@ func (e *defaultEventEmitter) Emit(event *types.Event) {
 	e.mu.RLock()
 	listeners := e.listeners[event.Type]
 	e.mu.RUnlock()
+	// Run the callbacks in order if synchronous.
+	if e.sync {
+		for _, l := range listeners {
+			l.callback(event)
+		}
+		return
+	}
 	// Determine the timeout for each callback.
 	var timeout *time.Duration
 	if e.timeout != nil {
@@ -2,6 +2,7 @@ package util
 
 import (
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -213,3 +214,135 @@ func TestConcurrentEmit(t *testing.T) {
 		t.Error("timeout waiting for concurrent emits")
 	}
 }
+
+// TestWithSyncOption tests that synchronous callbacks run in registration
+// order before Emit returns.
+func TestWithSyncOption(t *testing.T) {
+	emitter := NewEventEmitter().WithSync()
+	order := []int{}
+	for i := 0; i < 3; i++ {
+		emitter.RegisterListener("sync", func(e *types.Event) {
+			order = append(order, i)
+		})
+	}
+
+	emitter.Emit(types.NewEvent("sync", "first"))
+	emitter.Emit(types.NewEvent("sync", "second"))
+	assert.Equal(t, []int{0, 1, 2, 0, 1, 2}, order)
+}
+
+// TestWithSyncIgnoresTimeout tests that the timeout does not apply to
+// synchronous callbacks.
+func TestWithSyncIgnoresTimeout(t *testing.T) {
+	timeoutDuration := 10 * time.Millisecond
+	emitter := NewEventEmitter().WithTimeout(&timeoutDuration).WithSync()
+	require.True(t, emitter.sync)
+
+	done := false
+	emitter.RegisterListener("slow", func(e *types.Event) {
+		time.Sleep(50 * time.Millisecond)
+		done = true
+	})
+	emitter.Emit(types.NewEvent("slow", "slow"))
+	assert.True(t, done, "Emit should wait for the synchronous callback")
+}
+
+// TestRemoveAllListeners tests removing all listeners of one event type and
+// of all event types.
+func TestRemoveAllListeners(t *testing.T) {
+	emitter := NewEventEmitter()
+	noop := func(e *types.Event) {}
+	emitter.RegisterListener("a", noop)
+	emitter.RegisterListener("a", noop)
+	emitter.RegisterListener("b", noop)
+	assert.Equal(t, 2, emitter.ListenerCount("a"))
+	assert.Equal(t, 1, emitter.ListenerCount("b"))
+	assert.Equal(t, 0, emitter.ListenerCount("c"))
+
+	emitter.RemoveAllListeners("a")
+	assert.Equal(t, 0, emitter.ListenerCount("a"))
+	assert.Equal(t, 1, emitter.ListenerCount("b"))
+
+	emitter.RegisterListener("a", noop)
+	emitter.RemoveAllListenersForAllTypes()
+	assert.Equal(t, 0, emitter.ListenerCount("a"))
+	assert.Equal(t, 0, emitter.ListenerCount("b"))
+
+	emitter.RegisterListener("a", noop)
+	assert.Equal(t, 1, emitter.ListenerCount("a"))
+}
+
+// TestRemoveAllListenersConcurrent tests that removal is safe while events
+// are emitted and listeners are registered concurrently.
+func TestRemoveAllListenersConcurrent(t *testing.T) {
+	emitter := NewEventEmitter().WithSync()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			emitter.RegisterListener("c", func(e *types.Event) {})
+		}()
+		go func() {
+			defer wg.Done()
+			emitter.Emit(types.NewEvent("c", "concurrent"))
+		}()
+		go func() {
+			defer wg.Done()
+			emitter.RemoveAllListeners("c")
+			_ = emitter.ListenerCount("c")
+		}()
+	}
+	wg.Wait()
+	emitter.RemoveAllListenersForAllTypes()
+	assert.Equal(t, 0, emitter.ListenerCount("c"))
+}
+
+// TestRegisterOnce tests that a once-listener is invoked exactly once and
+// then removed, even with concurrent emits.
+func TestRegisterOnce(t *testing.T) {
+	for _, emitter := range []*defaultEventEmitter{
+		NewEventEmitter(), NewEventEmitter().WithSync(),
+	} {
+		var count atomic.Int32
+		called := make(chan struct{}, 100)
+		id := emitter.RegisterOnce("once", func(e *types.Event) {
+			count.Add(1)
+			called <- struct{}{}
+		})
+		assert.Equal(t, "once-1", id)
+		assert.Equal(t, 1, emitter.ListenerCount("once"))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				emitter.Emit(types.NewEvent("once", "once"))
+			}()
+		}
+		wg.Wait()
+
+		select {
+		case <-called:
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("timeout waiting for once-listener")
+		}
+		<-time.After(50 * time.Millisecond)
+		assert.Equal(t, int32(1), count.Load())
+		assert.Equal(t, 0, emitter.ListenerCount("once"))
+	}
+}
+
+// TestRegisterOnceRemove tests that a once-listener can be removed by its ID
+// before it fires.
+func TestRegisterOnceRemove(t *testing.T) {
+	emitter := NewEventEmitter().WithSync()
+	called := false
+	id := emitter.RegisterOnce("once", func(e *types.Event) {
+		called = true
+	})
+	emitter.RemoveListener("once", id)
+	emitter.Emit(types.NewEvent("once", "once"))
+	assert.False(t, called)
+}
@@ -0,0 +1,162 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pureapi/pureapi-core/util/types"
+)
+
+// logContextKey is the type of the logging context keys.
+type logContextKey string
+
+// Context keys for the logger and the log fields.
+const (
+	loggerKey    logContextKey = "logger"
+	logFieldsKey logContextKey = "log_fields"
+)
+
+// FieldLogger is a logger that can create a child logger with key/value
+// fields. Loggers that do not implement it get the fields appended to each
+// message.
+type FieldLogger interface {
+	types.ILogger
+	WithFields(keyvals ...any) types.ILogger
+}
+
+// WithLogger returns a new context carrying the logger.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - logger: The logger to carry.
+//
+// Returns:
+//   - context.Context: The new context.
+func WithLogger(ctx context.Context, logger types.ILogger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// WithLogFields returns a new context carrying the key/value fields in
+// addition to the fields of the parent context.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - keyvals: Alternating keys and values.
+//
+// Returns:
+//   - context.Context: The new context.
+func WithLogFields(ctx context.Context, keyvals ...any) context.Context {
+	parent := LogFieldsFromContext(ctx)
+	fields := make([]any, 0, len(parent)+len(keyvals))
+	fields = append(append(fields, parent...), keyvals...)
+	return context.WithValue(ctx, logFieldsKey, fields)
+}
+
+// LogFieldsFromContext returns the key/value fields carried by the context.
+//
+// Parameters:
+//   - ctx: The context to read from.
+//
+// Returns:
+//   - []any: Alternating keys and values, or nil if there are none.
+func LogFieldsFromContext(ctx context.Context) []any {
+	fields, _ := ctx.Value(logFieldsKey).([]any)
+	return fields
+}
+
+// LoggerFromContext returns the logger carried by the context with the
+// context's log fields attached. If the context has no logger, a no-op
+// logger is returned.
+//
+// Parameters:
+//   - ctx: The context to read from.
+//
+// Returns:
+//   - types.ILogger: The logger.
+func LoggerFromContext(ctx context.Context) types.ILogger {
+	logger, ok := ctx.Value(loggerKey).(types.ILogger)
+	if !ok || logger == nil {
+		return noopLogger{}
+	}
+	fields := LogFieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return logger
+	}
+	if fieldLogger, ok := logger.(FieldLogger); ok {
+		return fieldLogger.WithFields(fields...)
+	}
+	return &fieldsLogger{logger: logger, suffix: formatFields(fields)}
+}
+
+// formatFields formats key/value fields as " key=value" pairs.
+func formatFields(fields []any) string {
+	var builder strings.Builder
+	for i := 0; i < len(fields); i += 2 {
+		if i+1 < len(fields) {
+			fmt.Fprintf(&builder, " %v=%v", fields[i], fields[i+1])
+		} else {
+			fmt.Fprintf(&builder, " %v", fields[i])
+		}
+	}
+	return builder.String()
+}
+
+// fieldsLogger appends formatted fields to the messages of a logger.
+type fieldsLogger struct {
+	logger types.ILogger
+	suffix string
+}
+
+func (l *fieldsLogger) Debug(messages ...any) {
+	l.logger.Debug(fmt.Sprint(messages...) + l.suffix)
+}
+func (l *fieldsLogger) Debugf(message string, params ...any) {
+	l.logger.Debug(fmt.Sprintf(message, params...) + l.suffix)
+}
+func (l *fieldsLogger) Trace(messages ...any) {
+	l.logger.Trace(fmt.Sprint(messages...) + l.suffix)
+}
+func (l *fieldsLogger) Tracef(message string, params ...any) {
+	l.logger.Trace(fmt.Sprintf(message, params...) + l.suffix)
+}
+func (l *fieldsLogger) Info(messages ...any) {
+	l.logger.Info(fmt.Sprint(messages...) + l.suffix)
+}
+func (l *fieldsLogger) Infof(message string, params ...any) {
+	l.logger.Info(fmt.Sprintf(message, params...) + l.suffix)
+}
+func (l *fieldsLogger) Warn(messages ...any) {
+	l.logger.Warn(fmt.Sprint(messages...) + l.suffix)
+}
+func (l *fieldsLogger) Warnf(message string, params ...any) {
+	l.logger.Warn(fmt.Sprintf(message, params...) + l.suffix)
+}
+func (l *fieldsLogger) Error(messages ...any) {
+	l.logger.Error(fmt.Sprint(messages...) + l.suffix)
+}
+func (l *fieldsLogger) Errorf(message string, params ...any) {
+	l.logger.Error(fmt.Sprintf(message, params...) + l.suffix)
+}
+func (l *fieldsLogger) Fatal(messages ...any) {
+	l.logger.Fatal(fmt.Sprint(messages...) + l.suffix)
+}
+func (l *fieldsLogger) Fatalf(message string, params ...any) {
+	l.logger.Fatal(fmt.Sprintf(message, params...) + l.suffix)
+}
+
+// noopLogger is a logger that does nothing.
+type noopLogger struct{}
+
+func (noopLogger) Debug(messages ...any)                {}
+func (noopLogger) Debugf(message string, params ...any) {}
+func (noopLogger) Trace(messages ...any)                {}
+func (noopLogger) Tracef(message string, params ...any) {}
+func (noopLogger) Info(messages ...any)                 {}
+func (noopLogger) Infof(message string, params ...any)  {}
+func (noopLogger) Warn(messages ...any)                 {}
+func (noopLogger) Warnf(message string, params ...any)  {}
+func (noopLogger) Error(messages ...any)                {}
+func (noopLogger) Errorf(message string, params ...any) {}
+func (noopLogger) Fatal(messages ...any)                {}
+func (noopLogger) Fatalf(message string, params ...any) {}
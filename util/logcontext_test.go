@@ -0,0 +1,79 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// LogContextTestSuite defines a test suite for the context logger.
+type LogContextTestSuite struct {
+	suite.Suite
+}
+
+// TestLogContextTestSuite runs the test suite.
+func TestLogContextTestSuite(t *testing.T) {
+	suite.Run(t, new(LogContextTestSuite))
+}
+
+// Test_WithLogFields_Inheritance verifies that child contexts inherit the
+// fields of their parents without modifying them.
+func (s *LogContextTestSuite) Test_WithLogFields_Inheritance() {
+	s.Nil(LogFieldsFromContext(context.Background()))
+
+	parent := WithLogFields(context.Background(), "request_id", "abc")
+	childA := WithLogFields(parent, "user", "a")
+	childB := WithLogFields(parent, "user", "b")
+
+	s.Equal([]any{"request_id", "abc"}, LogFieldsFromContext(parent))
+	s.Equal(
+		[]any{"request_id", "abc", "user", "a"}, LogFieldsFromContext(childA),
+	)
+	s.Equal(
+		[]any{"request_id", "abc", "user", "b"}, LogFieldsFromContext(childB),
+	)
+}
+
+// Test_LoggerFromContext_NoLogger verifies the no-op fallback.
+func (s *LogContextTestSuite) Test_LoggerFromContext_NoLogger() {
+	logger := LoggerFromContext(
+		WithLogFields(context.Background(), "request_id", "abc"),
+	)
+	s.NotNil(logger)
+	s.NotPanics(func() {
+		logger.Info("ignored")
+		logger.Errorf("ignored %d", 1)
+	})
+}
+
+// Test_LoggerFromContext_FieldLogger verifies that field loggers receive the
+// fields as structured fields.
+func (s *LogContextTestSuite) Test_LoggerFromContext_FieldLogger() {
+	handler := newCaptureHandler()
+	ctx := WithLogger(context.Background(), NewSlogLogger(slog.New(handler)))
+	ctx = WithLogFields(ctx, "request_id", "abc")
+	ctx = WithLogFields(ctx, "user", "u1")
+
+	LoggerFromContext(ctx).Info("hello")
+	s.Require().Len(*handler.records, 1)
+	record := (*handler.records)[0]
+	s.Equal("hello", record.message)
+	s.Equal("abc", record.attrs["request_id"])
+	s.Equal("u1", record.attrs["user"])
+}
+
+// Test_LoggerFromContext_PlainLogger verifies that the fields are appended
+// to the messages of loggers without field support.
+func (s *LogContextTestSuite) Test_LoggerFromContext_PlainLogger() {
+	fake := &FakeLogger{}
+	ctx := WithLogger(context.Background(), fake)
+	LoggerFromContext(ctx).Warn("no fields")
+	s.Equal("no fields", fake.LastMessage)
+
+	ctx = WithLogFields(ctx, "request_id", "abc", "odd")
+	LoggerFromContext(ctx).Warnf("with %s", "fields")
+	s.Equal("Warn", fake.LastCalledMethod)
+	s.Equal("with fields request_id=abc odd", fake.LastMessage)
+}
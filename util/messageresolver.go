@@ -0,0 +1,63 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/pureapi/pureapi-core/util/types"
+)
+
+// mapMessageResolver resolves messages from a map of locales to messages.
+type mapMessageResolver struct {
+	messages map[string]map[string]string
+}
+
+// mapMessageResolver implements the MessageResolver interface.
+var _ types.MessageResolver = (*mapMessageResolver)(nil)
+
+// NewMapMessageResolver creates a new map-backed message resolver.
+//
+// Parameters:
+//   - messages: The messages keyed by locale and then by error ID.
+//
+// Returns:
+//   - *mapMessageResolver: A new mapMessageResolver instance.
+func NewMapMessageResolver(
+	messages map[string]map[string]string,
+) *mapMessageResolver {
+	return &mapMessageResolver{
+		messages: messages,
+	}
+}
+
+// Resolve returns the message for the ID in the locale. If the locale has
+// no message, the base language of the locale is tried, e.g. "fi" for
+// "fi-FI". Locales are matched case-insensitively.
+//
+// Parameters:
+//   - id: The error ID.
+//   - locale: The locale, such as "en" or "en-US".
+//   - data: The error data. It is not used by this resolver.
+//
+// Returns:
+//   - string: The resolved message.
+//   - bool: True if a message was found.
+func (r *mapMessageResolver) Resolve(
+	id string, locale string, data any,
+) (string, bool) {
+	locale = strings.ToLower(locale)
+	for {
+		for key, messages := range r.messages {
+			if strings.ToLower(key) != locale {
+				continue
+			}
+			if message, ok := messages[id]; ok {
+				return message, true
+			}
+		}
+		index := strings.LastIndex(locale, "-")
+		if index < 0 {
+			return "", false
+		}
+		locale = locale[:index]
+	}
+}
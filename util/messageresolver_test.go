@@ -0,0 +1,53 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// MessageResolverTestSuite defines a test suite for the message resolver.
+type MessageResolverTestSuite struct {
+	suite.Suite
+	resolver *mapMessageResolver
+}
+
+// TestMessageResolverTestSuite runs the test suite.
+func TestMessageResolverTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageResolverTestSuite))
+}
+
+// SetupTest creates the resolver used by the tests.
+func (s *MessageResolverTestSuite) SetupTest() {
+	s.resolver = NewMapMessageResolver(map[string]map[string]string{
+		"en":    {"not_found": "Not found"},
+		"fi":    {"not_found": "Ei löytynyt"},
+		"fi-SV": {"not_found": "Hittades inte"},
+	})
+}
+
+// Test_Resolve verifies exact and base language matches.
+func (s *MessageResolverTestSuite) Test_Resolve() {
+	message, ok := s.resolver.Resolve("not_found", "fi-sv", nil)
+	s.True(ok)
+	s.Equal("Hittades inte", message)
+
+	message, ok = s.resolver.Resolve("not_found", "en-US", nil)
+	s.True(ok)
+	s.Equal("Not found", message)
+
+	_, ok = s.resolver.Resolve("not_found", "de", nil)
+	s.False(ok)
+	_, ok = s.resolver.Resolve("unknown", "en", nil)
+	s.False(ok)
+}
+
+// Test_LocalizedMessage verifies that the raw message is used when the
+// locale is missing.
+func (s *MessageResolverTestSuite) Test_LocalizedMessage() {
+	apiErr := NewAPIError("not_found").WithMessage("raw")
+
+	s.Equal("Ei löytynyt", apiErr.LocalizedMessage(s.resolver, "fi"))
+	s.Equal("raw", apiErr.LocalizedMessage(s.resolver, "de"))
+	s.Equal("raw", apiErr.LocalizedMessage(nil, "fi"))
+}
@@ -0,0 +1,36 @@
+package util
+
+import "context"
+
+// RequestIDLogField is the log field under which the request ID is added to
+// the context log fields.
+const RequestIDLogField = "request_id"
+
+// requestIDKey is the context key of the request ID.
+const requestIDKey logContextKey = "request_id"
+
+// WithRequestID returns a new context carrying the request ID, also added to
+// the context log fields under RequestIDLogField.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - id: The request ID.
+//
+// Returns:
+//   - context.Context: The new context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey, id)
+	return WithLogFields(ctx, RequestIDLogField, id)
+}
+
+// RequestIDFromContext returns the request ID carried by the context.
+//
+// Parameters:
+//   - ctx: The context to read from.
+//
+// Returns:
+//   - string: The request ID, or an empty string if none is set.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
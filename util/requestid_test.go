@@ -0,0 +1,28 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// RequestIDTestSuite defines a test suite for the request ID context helpers.
+type RequestIDTestSuite struct {
+	suite.Suite
+}
+
+// TestRequestIDTestSuite runs the test suite.
+func TestRequestIDTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestIDTestSuite))
+}
+
+// Test_WithRequestID verifies that the request ID is stored in the context
+// and added to the log fields.
+func (s *RequestIDTestSuite) Test_WithRequestID() {
+	s.Empty(RequestIDFromContext(context.Background()))
+
+	ctx := WithRequestID(context.Background(), "abc")
+	s.Equal("abc", RequestIDFromContext(ctx))
+	s.Equal([]any{RequestIDLogField, "abc"}, LogFieldsFromContext(ctx))
+}
@@ -0,0 +1,158 @@
+package util
+
+import (
+	"io"
+	"net/http"
+)
+
+// ResWrap wraps an http.ResponseWriter and captures the status code, the
+// number of bytes and the body written through it.
+type ResWrap struct {
+	http.ResponseWriter
+	statusCode   int
+	wroteHeader  bool
+	body         []byte
+	bytesWritten int
+	sizeOnly     bool
+}
+
+// NewResWrap creates a new ResWrap. The status code defaults to 200 until
+// WriteHeader is called.
+//
+// Parameters:
+//   - w: The response writer to wrap.
+//
+// Returns:
+//   - *ResWrap: A new ResWrap instance.
+func NewResWrap(w http.ResponseWriter) *ResWrap {
+	return &ResWrap{
+		ResponseWriter: w,
+		statusCode:     http.StatusOK,
+	}
+}
+
+// WithSizeOnly returns a new ResWrap that does not buffer the body. Body
+// returns nil but BytesWritten stays accurate, which bounds memory use on
+// large responses.
+//
+// Returns:
+//   - *ResWrap: A new ResWrap instance.
+func (rw *ResWrap) WithSizeOnly() *ResWrap {
+	new := *rw
+	new.sizeOnly = true
+	new.body = nil
+	return &new
+}
+
+// WriteHeader captures and writes the status code. Only the first call is
+// captured, matching the behavior of http.ResponseWriter.
+//
+// Parameters:
+//   - statusCode: The status code.
+func (rw *ResWrap) WriteHeader(statusCode int) {
+	if !rw.wroteHeader {
+		rw.statusCode = statusCode
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write captures and writes the data. Only the byte count is captured in
+// size-only mode.
+//
+// Parameters:
+//   - data: The data to write.
+//
+// Returns:
+//   - int: The number of bytes written.
+//   - error: An error if writing fails.
+func (rw *ResWrap) Write(data []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(data)
+	rw.bytesWritten += n
+	if !rw.sizeOnly {
+		rw.body = append(rw.body, data[:n]...)
+	}
+	return n, err
+}
+
+// ReadFrom writes the data read from src. It delegates to the underlying
+// writer's ReadFrom when available, which lets http.ResponseWriter use
+// sendfile in size-only mode. In buffered mode the data is also captured,
+// so the reader is wrapped. Otherwise it falls back to a buffered copy.
+//
+// Parameters:
+//   - src: The reader to read from.
+//
+// Returns:
+//   - int64: The number of bytes written.
+//   - error: An error if reading or writing fails.
+func (rw *ResWrap) ReadFrom(src io.Reader) (int64, error) {
+	rw.wroteHeader = true
+	if !rw.sizeOnly {
+		src = io.TeeReader(src, bodyWriter{rw: rw})
+	}
+	var n int64
+	var err error
+	if readerFrom, ok := rw.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = readerFrom.ReadFrom(src)
+	} else {
+		n, err = io.Copy(rw.ResponseWriter, src)
+	}
+	rw.bytesWritten += int(n)
+	if !rw.sizeOnly && len(rw.body) > rw.bytesWritten {
+		// Drop data that was read but not written.
+		rw.body = rw.body[:rw.bytesWritten]
+	}
+	return n, err
+}
+
+// bodyWriter appends the written data to the captured body.
+type bodyWriter struct {
+	rw *ResWrap
+}
+
+// Write appends the data to the captured body.
+func (w bodyWriter) Write(data []byte) (int, error) {
+	w.rw.body = append(w.rw.body, data...)
+	return len(data), nil
+}
+
+// Flush flushes the underlying writer if it supports flushing.
+func (rw *ResWrap) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+//
+// Returns:
+//   - http.ResponseWriter: The underlying writer.
+func (rw *ResWrap) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// StatusCode returns the captured status code.
+//
+// Returns:
+//   - int: The status code.
+func (rw *ResWrap) StatusCode() int {
+	return rw.statusCode
+}
+
+// BytesWritten returns the number of body bytes written.
+//
+// Returns:
+//   - int: The number of bytes written.
+func (rw *ResWrap) BytesWritten() int {
+	return rw.bytesWritten
+}
+
+// Body returns the captured body, or nil in size-only mode.
+//
+// Returns:
+//   - []byte: The body.
+func (rw *ResWrap) Body() []byte {
+	return rw.body
+}
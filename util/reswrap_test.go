@@ -0,0 +1,126 @@
+package util
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// readerFromWriter is a response writer that implements io.ReaderFrom and
+// records the reader it was given.
+type readerFromWriter struct {
+	*httptest.ResponseRecorder
+	src io.Reader
+}
+
+func (w *readerFromWriter) ReadFrom(src io.Reader) (int64, error) {
+	w.src = src
+	return io.Copy(w.ResponseRecorder, src)
+}
+
+// ResWrapTestSuite is a suite of tests for ResWrap.
+type ResWrapTestSuite struct {
+	suite.Suite
+}
+
+// TestResWrapTestSuite runs the test suite.
+func TestResWrapTestSuite(t *testing.T) {
+	suite.Run(t, new(ResWrapTestSuite))
+}
+
+// Test_Capture tests that the status code and body are captured and written
+// to the underlying writer.
+func (s *ResWrapTestSuite) Test_Capture() {
+	rec := httptest.NewRecorder()
+	rw := NewResWrap(rec)
+	rw.WriteHeader(http.StatusCreated)
+	rw.WriteHeader(http.StatusTeapot)
+	_, err := rw.Write([]byte("hello "))
+	s.Require().NoError(err)
+	_, err = rw.Write([]byte("world"))
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusCreated, rw.StatusCode())
+	s.Equal("hello world", string(rw.Body()))
+	s.Equal(11, rw.BytesWritten())
+	s.Equal(http.StatusCreated, rec.Code)
+	s.Equal("hello world", rec.Body.String())
+}
+
+// Test_SizeOnly tests that only the byte count is captured in size-only
+// mode.
+func (s *ResWrapTestSuite) Test_SizeOnly() {
+	rec := httptest.NewRecorder()
+	rw := NewResWrap(rec).WithSizeOnly()
+	_, err := rw.Write([]byte("hello "))
+	s.Require().NoError(err)
+	_, err = rw.Write([]byte("world"))
+	s.Require().NoError(err)
+
+	s.Nil(rw.Body())
+	s.Equal(11, rw.BytesWritten())
+	s.Equal("hello world", rec.Body.String())
+}
+
+// Test_DefaultStatus tests that the status code defaults to 200.
+func (s *ResWrapTestSuite) Test_DefaultStatus() {
+	rw := NewResWrap(httptest.NewRecorder())
+	_, _ = rw.Write([]byte("x"))
+	rw.WriteHeader(http.StatusNotFound)
+	s.Equal(http.StatusOK, rw.StatusCode())
+}
+
+// Test_Flush tests that Flush reaches the underlying writer.
+func (s *ResWrapTestSuite) Test_Flush() {
+	rec := httptest.NewRecorder()
+	rw := NewResWrap(rec)
+	rw.Flush()
+	s.True(rec.Flushed)
+	s.Same(rec, rw.Unwrap())
+}
+
+// Test_ReadFrom_Delegates tests that ReadFrom delegates to the underlying
+// writer and counts the bytes in both modes.
+func (s *ResWrapTestSuite) Test_ReadFrom_Delegates() {
+	w := &readerFromWriter{ResponseRecorder: httptest.NewRecorder()}
+	rw := NewResWrap(w).WithSizeOnly()
+	// Hide WriteTo so that io.Copy uses ReadFrom.
+	src := struct{ io.Reader }{strings.NewReader("large file")}
+
+	n, err := io.Copy(rw, src)
+	s.Require().NoError(err)
+	s.Equal(int64(10), n)
+	s.Equal(src, w.src, "size-only mode should pass the reader through")
+	s.Equal(10, rw.BytesWritten())
+	s.Nil(rw.Body())
+	s.Equal("large file", w.Body.String())
+
+	w = &readerFromWriter{ResponseRecorder: httptest.NewRecorder()}
+	rw = NewResWrap(w)
+	_, err = rw.Write([]byte("head "))
+	s.Require().NoError(err)
+	_, err = rw.ReadFrom(strings.NewReader("large file"))
+	s.Require().NoError(err)
+	s.NotNil(w.src)
+	s.Equal(15, rw.BytesWritten())
+	s.Equal("head large file", string(rw.Body()))
+	s.Equal("head large file", w.Body.String())
+}
+
+// Test_ReadFrom_Fallback tests that ReadFrom copies when the underlying
+// writer does not implement io.ReaderFrom.
+func (s *ResWrapTestSuite) Test_ReadFrom_Fallback() {
+	rec := httptest.NewRecorder()
+	rw := NewResWrap(rec)
+
+	n, err := rw.ReadFrom(strings.NewReader("data"))
+	s.Require().NoError(err)
+	s.Equal(int64(4), n)
+	s.Equal(4, rw.BytesWritten())
+	s.Equal("data", string(rw.Body()))
+	s.Equal("data", rec.Body.String())
+}
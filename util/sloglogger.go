@@ -0,0 +1,177 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/pureapi/pureapi-core/util/types"
+)
+
+// LevelFatal is the slog level used for Fatal messages. It is above
+// slog.LevelError.
+const LevelFatal = slog.LevelError + 4
+
+// slogLogger adapts a slog.Logger to the ILogger interface.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// slogLogger implements the FieldLogger interface.
+var _ FieldLogger = (*slogLogger)(nil)
+
+// NewSlogLogger creates a new ILogger that logs with the given slog.Logger.
+// Trace is logged at slog.LevelDebug and Fatal at LevelFatal. Fatal does not
+// exit the program.
+//
+// Parameters:
+//   - logger: The slog logger. If nil, slog.Default() is used.
+//
+// Returns:
+//   - *slogLogger: A new slogLogger instance.
+func NewSlogLogger(logger *slog.Logger) *slogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{
+		logger: logger,
+	}
+}
+
+// SlogLoggerFactoryFn returns a logger factory function for
+// NewEmitterLogger. The factory parameters are added to the logger as
+// slog key/value attributes.
+//
+// Parameters:
+//   - logger: The slog logger. If nil, slog.Default() is used.
+//
+// Returns:
+//   - types.LoggerFactoryFn: The logger factory function.
+func SlogLoggerFactoryFn(logger *slog.Logger) types.LoggerFactoryFn {
+	base := NewSlogLogger(logger)
+	return func(params ...any) types.ILogger {
+		if len(params) == 0 {
+			return base
+		}
+		return base.WithFields(params...)
+	}
+}
+
+// WithFields returns a new logger with the key/value fields added as slog
+// attributes.
+//
+// Parameters:
+//   - keyvals: Alternating keys and values.
+//
+// Returns:
+//   - types.ILogger: A new slogLogger instance.
+func (l *slogLogger) WithFields(keyvals ...any) types.ILogger {
+	return NewSlogLogger(l.logger.With(keyvals...))
+}
+
+// Debug logs at the Debug level.
+//
+// Parameters:
+//   - messages: The messages to log.
+func (l *slogLogger) Debug(messages ...any) {
+	l.log(slog.LevelDebug, fmt.Sprint(messages...))
+}
+
+// Debugf logs at the Debug level.
+//
+// Parameters:
+//   - message: The message to log.
+//   - params: The parameters to use in the message.
+func (l *slogLogger) Debugf(message string, params ...any) {
+	l.log(slog.LevelDebug, fmt.Sprintf(message, params...))
+}
+
+// Trace logs at the Debug level.
+//
+// Parameters:
+//   - messages: The messages to log.
+func (l *slogLogger) Trace(messages ...any) {
+	l.log(slog.LevelDebug, fmt.Sprint(messages...))
+}
+
+// Tracef logs at the Debug level.
+//
+// Parameters:
+//   - message: The message to log.
+//   - params: The parameters to use in the message.
+func (l *slogLogger) Tracef(message string, params ...any) {
+	l.log(slog.LevelDebug, fmt.Sprintf(message, params...))
+}
+
+// Info logs at the Info level.
+//
+// Parameters:
+//   - messages: The messages to log.
+func (l *slogLogger) Info(messages ...any) {
+	l.log(slog.LevelInfo, fmt.Sprint(messages...))
+}
+
+// Infof logs at the Info level.
+//
+// Parameters:
+//   - message: The message to log.
+//   - params: The parameters to use in the message.
+func (l *slogLogger) Infof(message string, params ...any) {
+	l.log(slog.LevelInfo, fmt.Sprintf(message, params...))
+}
+
+// Warn logs at the Warn level.
+//
+// Parameters:
+//   - messages: The messages to log.
+func (l *slogLogger) Warn(messages ...any) {
+	l.log(slog.LevelWarn, fmt.Sprint(messages...))
+}
+
+// Warnf logs at the Warn level.
+//
+// Parameters:
+//   - message: The message to log.
+//   - params: The parameters to use in the message.
+func (l *slogLogger) Warnf(message string, params ...any) {
+	l.log(slog.LevelWarn, fmt.Sprintf(message, params...))
+}
+
+// Error logs at the Error level.
+//
+// Parameters:
+//   - messages: The messages to log.
+func (l *slogLogger) Error(messages ...any) {
+	l.log(slog.LevelError, fmt.Sprint(messages...))
+}
+
+// Errorf logs at the Error level.
+//
+// Parameters:
+//   - message: The message to log.
+//   - params: The parameters to use in the message.
+func (l *slogLogger) Errorf(message string, params ...any) {
+	l.log(slog.LevelError, fmt.Sprintf(message, params...))
+}
+
+// Fatal logs at the Fatal level.
+//
+// Parameters:
+//   - messages: The messages to log.
+func (l *slogLogger) Fatal(messages ...any) {
+	l.log(LevelFatal, fmt.Sprint(messages...))
+}
+
+// Fatalf logs at the Fatal level.
+//
+// Parameters:
+//   - message: The message to log.
+//   - params: The parameters to use in the message.
+func (l *slogLogger) Fatalf(message string, params ...any) {
+	l.log(LevelFatal, fmt.Sprintf(message, params...))
+}
+
+// log logs the message at the level.
+func (l *slogLogger) log(level slog.Level, message string) {
+	l.logger.Log(context.Background(), level, message)
+}
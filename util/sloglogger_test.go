@@ -0,0 +1,115 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/pureapi/pureapi-core/util/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// capturedRecord is a log record captured by captureHandler.
+type capturedRecord struct {
+	level   slog.Level
+	message string
+	attrs   map[string]any
+}
+
+// captureHandler is a slog.Handler that records all log records.
+type captureHandler struct {
+	records *[]capturedRecord
+	attrs   []slog.Attr
+}
+
+func newCaptureHandler() *captureHandler {
+	return &captureHandler{records: &[]capturedRecord{}}
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := map[string]any{}
+	for _, attr := range h.attrs {
+		attrs[attr.Key] = attr.Value.Any()
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value.Any()
+		return true
+	})
+	*h.records = append(*h.records, capturedRecord{
+		level: r.Level, message: r.Message, attrs: attrs,
+	})
+	return nil
+}
+
+func (h *captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &captureHandler{
+		records: h.records,
+		attrs:   append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
+func (h *captureHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// SlogLoggerTestSuite defines a test suite for the slog logger.
+type SlogLoggerTestSuite struct {
+	suite.Suite
+}
+
+// TestSlogLoggerTestSuite runs the test suite.
+func TestSlogLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(SlogLoggerTestSuite))
+}
+
+// Test_LevelMapping verifies that each method logs at the mapped level.
+func (s *SlogLoggerTestSuite) Test_LevelMapping() {
+	handler := newCaptureHandler()
+	logger := NewSlogLogger(slog.New(handler))
+
+	logger.Debug("debug")
+	logger.Tracef("%s", "trace")
+	logger.Info("info")
+	logger.Warnf("warn %d", 1)
+	logger.Error("error", 2)
+	logger.Fatal("fatal")
+
+	expected := []struct {
+		level   slog.Level
+		message string
+	}{
+		{slog.LevelDebug, "debug"},
+		{slog.LevelDebug, "trace"},
+		{slog.LevelInfo, "info"},
+		{slog.LevelWarn, "warn 1"},
+		{slog.LevelError, "error2"},
+		{LevelFatal, "fatal"},
+	}
+	s.Require().Len(*handler.records, len(expected))
+	for i, record := range *handler.records {
+		s.Equal(expected[i].level, record.level)
+		s.Equal(expected[i].message, record.message)
+	}
+}
+
+// Test_FactoryFn verifies that the factory function works with the emitter
+// logger and adds the factory parameters as attributes.
+func (s *SlogLoggerTestSuite) Test_FactoryFn() {
+	handler := newCaptureHandler()
+	emitterLogger := NewEmitterLogger(
+		nil, SlogLoggerFactoryFn(slog.New(handler)),
+	)
+
+	emitterLogger.Warn(types.NewEvent("test", "warned"), "request_id", "abc")
+	emitterLogger.Info(types.NewEvent("test", "plain"))
+
+	s.Require().Len(*handler.records, 2)
+	s.Equal(slog.LevelWarn, (*handler.records)[0].level)
+	s.Equal("warned", (*handler.records)[0].message)
+	s.Equal("abc", (*handler.records)[0].attrs["request_id"])
+	s.Empty((*handler.records)[1].attrs)
+}
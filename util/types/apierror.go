@@ -8,3 +8,8 @@ type APIError interface {
 	Message() string
 	Origin() string
 }
+
+// MessageResolver resolves localized messages for API error IDs.
+type MessageResolver interface {
+	Resolve(id string, locale string, data any) (string, bool)
+}
@@ -47,6 +47,10 @@ type EventCallback func(event *Event)
 // EventEmitter is responsible for emitting events.
 type EventEmitter interface {
 	RegisterListener(eventType EventType, callback EventCallback) EventEmitter
+	RegisterOnce(eventType EventType, callback EventCallback) string
 	RemoveListener(eventType EventType, id string)
+	RemoveAllListeners(eventType EventType)
+	RemoveAllListenersForAllTypes()
+	ListenerCount(eventType EventType) int
 	Emit(event *Event)
 }
@@ -41,3 +41,16 @@ type LoggerFactoryFn func(params ...any) ILogger
 
 // CtxLoggerFactoryFn is a function that returns a logger with context.
 type CtxLoggerFactoryFn func(ctx context.Context) ILogger
+
+// LogLevel is the severity of a log message.
+type LogLevel int
+
+// Log levels in increasing severity.
+const (
+	LevelTrace LogLevel = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
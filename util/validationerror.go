@@ -0,0 +1,82 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a validation failure of a single input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationError collects the field errors of an input so that all of them
+// can be reported at once.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// NewValidationError returns a new validation error without field errors.
+//
+// Returns:
+//   - *ValidationError: A new ValidationError instance.
+func NewValidationError() *ValidationError {
+	return &ValidationError{
+		Fields: []FieldError{},
+	}
+}
+
+// AddField returns a new validation error with the field error added.
+//
+// Parameters:
+//   - field: The name of the invalid field.
+//   - code: The machine-readable code of the failure.
+//   - message: The human-readable message of the failure.
+//
+// Returns:
+//   - *ValidationError: A new ValidationError instance.
+func (e *ValidationError) AddField(
+	field string, code string, message string,
+) *ValidationError {
+	new := *e
+	new.Fields = append(
+		append([]FieldError{}, e.Fields...),
+		FieldError{Field: field, Code: code, Message: message},
+	)
+	return &new
+}
+
+// HasErrors reports whether any field errors have been added.
+//
+// Returns:
+//   - bool: True if there are field errors.
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+// ErrOrNil returns the validation error if it has field errors and nil
+// otherwise. It is meant to be returned at the end of a validation
+// function.
+//
+// Returns:
+//   - error: The validation error or nil.
+func (e *ValidationError) ErrOrNil() error {
+	if !e.HasErrors() {
+		return nil
+	}
+	return e
+}
+
+// Error returns the field errors as a single message.
+//
+// Returns:
+//   - string: The error message.
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = fmt.Sprintf("%s: %s", field.Field, field.Code)
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(fields, ", "))
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// ValidationErrorTestSuite defines a test suite for ValidationError.
+type ValidationErrorTestSuite struct {
+	suite.Suite
+}
+
+// TestValidationErrorTestSuite runs the test suite.
+func TestValidationErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationErrorTestSuite))
+}
+
+// Test_AddField verifies that AddField collects all field errors and returns
+// a new instance.
+func (s *ValidationErrorTestSuite) Test_AddField() {
+	base := NewValidationError()
+	s.False(base.HasErrors())
+	s.NoError(base.ErrOrNil())
+
+	err := base.
+		AddField("name", "required", "name is required").
+		AddField("age", "min", "")
+	s.Empty(base.Fields)
+	s.True(err.HasErrors())
+	s.Equal([]FieldError{
+		{Field: "name", Code: "required", Message: "name is required"},
+		{Field: "age", Code: "min"},
+	}, err.Fields)
+	s.Equal(err, err.ErrOrNil())
+	s.Equal("validation failed: name: required, age: min", err.Error())
+}
+
+// Test_MarshalJSON verifies the JSON form of the field errors.
+func (s *ValidationErrorTestSuite) Test_MarshalJSON() {
+	err := NewValidationError().AddField("name", "required", "")
+	data, marshalErr := json.Marshal(err)
+	s.Require().NoError(marshalErr)
+	s.JSONEq(
+		`{"fields":[{"field":"name","code":"required"}]}`, string(data),
+	)
+}